@@ -0,0 +1,76 @@
+package bls
+
+import (
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+// SignAugmented generates a BLS signature of the message augmented with the
+// public key of the signer: sk×H(pk, m). Such signatures may be aggregated
+// even if the messages are the same.
+func (secretKey PrivateKey) SignAugmented(message []byte) Signature {
+	return LegacyCiphersuite.SignAugmented(secretKey, message)
+}
+
+// AggregateSignaturesDistinct sums the signatures of distinct messages to be
+// checked by AggregateVerify
+func AggregateSignaturesDistinct(sigs []Signature, msgs [][]byte) (Signature, error) {
+	if len(sigs) != len(msgs) {
+		return Signature{}, ErrLengthMismatch
+	}
+	if !distinct(msgs) {
+		return Signature{}, ErrDuplicateMessage
+	}
+	p := new(bn256.G1).Set(&zeroG1)
+	for _, sig := range sigs {
+		p.Add(p, sig.p)
+	}
+	return Signature{p: p}, nil
+}
+
+// AggregateVerify checks the aggregated signature of the messages, each
+// signed by the corresponding public key:
+//
+// e(S, G) = e(H(m1), P1)⋅e(H(m2), P2)⋅...
+//
+// The messages must be distinct, otherwise the aggregation is open to rogue
+// key attacks and the check fails.
+func AggregateVerify(pubs []PublicKey, msgs [][]byte, sig Signature) bool {
+	return LegacyCiphersuite.AggregateVerify(pubs, msgs, sig)
+}
+
+// AggregateVerifyAugmented checks the aggregated signature of the messages
+// generated by SignAugmented. The messages need not be distinct.
+func AggregateVerifyAugmented(pubs []PublicKey, msgs [][]byte, sig Signature) bool {
+	return LegacyCiphersuite.AggregateVerifyAugmented(pubs, msgs, sig)
+}
+
+// aggregateVerify performs the n+1 pairing check of the aggregated signature
+// against the message points
+func aggregateVerify(pubs []PublicKey, points []*bn256.G1, sig Signature) bool {
+	if sig.p == nil {
+		return false
+	}
+	a := make([]*bn256.G1, 0, len(points)+1)
+	b := make([]*bn256.G2, 0, len(points)+1)
+	a = append(a, new(bn256.G1).Neg(sig.p))
+	b = append(b, &g2)
+	for i, point := range points {
+		if pubs[i].p == nil {
+			return false
+		}
+		a = append(a, point)
+		b = append(b, pubs[i].p)
+	}
+	return bn256.PairingCheck(a, b)
+}
+
+func distinct(msgs [][]byte) bool {
+	seen := make(map[string]struct{}, len(msgs))
+	for _, msg := range msgs {
+		if _, ok := seen[string(msg)]; ok {
+			return false
+		}
+		seen[string(msg)] = struct{}{}
+	}
+	return true
+}
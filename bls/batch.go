@@ -0,0 +1,114 @@
+package bls
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"io"
+	"math/big"
+	"sort"
+
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+// SignatureSet is a signature of the message by the public key to be
+// checked by BatchVerify
+type SignatureSet struct {
+	Pub PublicKey
+	Msg []byte
+	Sig Signature
+}
+
+// batchItem is a signature set multiplied by a random scalar r:
+// (r×S, r×H(m), P) with the index of the set
+type batchItem struct {
+	index int
+	sig   *bn256.G1
+	hash  *bn256.G1
+	pub   *bn256.G2
+}
+
+// BatchVerify checks many independent signatures at once and returns the
+// indices of the invalid ones (none if all the signatures are valid).
+//
+// The signatures are combined with small random coefficients into a single
+// multi-pairing check:
+//
+// e(r1×S1 + r2×S2 + ..., G) = e(r1×H(m1), P1)⋅e(r2×H(m2), P2)⋅...
+//
+// If it fails, the batch is split in halves recursively to find the invalid
+// signatures. Sets with zero-value keys or signatures and the keys at
+// infinity are reported invalid, as by Verify. The error is returned only if
+// the random coefficients can not be generated.
+func BatchVerify(sets []SignatureSet) ([]int, error) {
+	return LegacyCiphersuite.BatchVerify(sets)
+}
+
+func batchVerify(sets []SignatureSet, hashToPoint func([]byte) *bn256.G1) ([]int, error) {
+	var invalid []int
+	items := make([]batchItem, 0, len(sets))
+	for i, set := range sets {
+		if set.Pub.validate() != nil || set.Sig.validate() != nil {
+			invalid = append(invalid, i)
+			continue
+		}
+		r, err := randomBatchScalar()
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, batchItem{
+			index: i,
+			sig:   new(bn256.G1).ScalarMult(set.Sig.p, r),
+			hash:  new(bn256.G1).ScalarMult(hashToPoint(set.Msg), r),
+			pub:   set.Pub.p,
+		})
+	}
+	if len(items) > 0 && !verifyBatch(items) {
+		invalid = findInvalid(items, invalid)
+		sort.Ints(invalid)
+	}
+	return invalid, nil
+}
+
+// findInvalid bisects the failed batch and appends the indices of invalid
+// signatures
+func findInvalid(items []batchItem, invalid []int) []int {
+	if len(items) == 1 {
+		return append(invalid, items[0].index)
+	}
+	half := len(items) / 2
+	if !verifyBatch(items[:half]) {
+		invalid = findInvalid(items[:half], invalid)
+	}
+	if !verifyBatch(items[half:]) {
+		invalid = findInvalid(items[half:], invalid)
+	}
+	return invalid
+}
+
+func verifyBatch(items []batchItem) bool {
+	sum := new(bn256.G1).Set(&zeroG1)
+	a := make([]*bn256.G1, 0, len(items)+1)
+	b := make([]*bn256.G2, 0, len(items)+1)
+	a = append(a, sum)
+	b = append(b, &g2)
+	for _, item := range items {
+		sum.Add(sum, item.sig)
+		a = append(a, item.hash)
+		b = append(b, item.pub)
+	}
+	sum.Neg(sum)
+	return bn256.PairingCheck(a, b)
+}
+
+// randomBatchScalar returns a random non-zero 64-bit coefficient
+func randomBatchScalar() (*big.Int, error) {
+	var buf [8]byte
+	for {
+		if _, err := io.ReadFull(rand.Reader, buf[:]); err != nil {
+			return nil, err
+		}
+		if r := binary.BigEndian.Uint64(buf[:]); r != 0 {
+			return new(big.Int).SetUint64(r), nil
+		}
+	}
+}
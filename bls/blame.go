@@ -0,0 +1,149 @@
+package bls
+
+import (
+	"sort"
+
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+// Contribution is the partial signature generated by Multisign of the group
+// member with the given index
+type Contribution struct {
+	Index     uint32
+	Signature Signature
+}
+
+// Blame finds the invalid contributions to the multisignature of the message
+// by the group with the given public keys. It returns the multisignature of
+// the valid contributions and the indices of the invalid ones.
+//
+// The contributions are checked together and split in halves recursively
+// while the check fails, so a few invalid ones among many take a few checks.
+// As by BatchVerify, each check combines the contributions with fresh random
+// coefficients, so invalid contributions can not cancel out each other:
+//
+// e(r1×S1 + r2×S2 + ..., G) = e(H(P, m), r1×pk1 + r2×pk2 + ...)⋅e(r1×H(P, i1) + r2×H(P, i2) + ..., P)
+//
+// The message and the indices are hashed by LegacyMultisigSuite.
+func Blame(aggPublicKey PublicKey, pubs []PublicKey, message []byte, contributions []Contribution) (Multisig, []uint32, error) {
+	return LegacyMultisigSuite.Blame(aggPublicKey, pubs, message, contributions)
+}
+
+// Blame finds the invalid contributions to the multisignature of the
+// message hashed by the suite, see Blame
+func (suite MultisigSuite) Blame(aggPublicKey PublicKey, pubs []PublicKey, message []byte, contributions []Contribution) (Multisig, []uint32, error) {
+	if err := aggPublicKey.validate(); err != nil {
+		return Multisig{}, nil, err
+	}
+	var invalid []uint32
+	candidates := make([]Contribution, 0, len(contributions))
+	seen := make(map[uint32]struct{}, len(contributions))
+	for _, contribution := range contributions {
+		if int64(contribution.Index) >= int64(len(pubs)) {
+			return Multisig{}, nil, ErrIndexRange
+		}
+		if _, ok := seen[contribution.Index]; ok {
+			return Multisig{}, nil, ErrDuplicateSigner
+		}
+		seen[contribution.Index] = struct{}{}
+		if pubs[contribution.Index].validate() != nil || contribution.Signature.validate() != nil {
+			invalid = append(invalid, contribution.Index)
+			continue
+		}
+		candidates = append(candidates, contribution)
+	}
+
+	items := make([]blameItem, len(candidates))
+	for i, contribution := range candidates {
+		items[i] = blameItem{
+			index: contribution.Index,
+			sig:   contribution.Signature.p,
+			pub:   pubs[contribution.Index].p,
+			hash:  suite.hashToPointIndex(aggPublicKey.p, contribution.Index),
+		}
+	}
+	msgHash := suite.hashToPointMsg(aggPublicKey.p, message)
+	verify := func(items []blameItem) (bool, error) {
+		return verifyBlameItems(items, aggPublicKey.p, msgHash)
+	}
+	if len(items) > 0 {
+		var err error
+		if invalid, err = findInvalidContributions(items, verify, invalid); err != nil {
+			return Multisig{}, nil, err
+		}
+	}
+	sort.Slice(invalid, func(i, j int) bool { return invalid[i] < invalid[j] })
+
+	valid := make([]Contribution, 0, len(candidates))
+	for _, contribution := range candidates {
+		if !containsIndex(invalid, contribution.Index) {
+			valid = append(valid, contribution)
+		}
+	}
+	return aggregateContributions(pubs, valid), invalid, nil
+}
+
+// blameItem is the partial signature S of the member with the index, its
+// public key pk and H(P, i)
+type blameItem struct {
+	index uint32
+	sig   *bn256.G1
+	pub   *bn256.G2
+	hash  *bn256.G1
+}
+
+// findInvalidContributions checks the contributions and bisects them if the
+// check fails, appending the indices of invalid ones
+func findInvalidContributions(items []blameItem, verify func([]blameItem) (bool, error), invalid []uint32) ([]uint32, error) {
+	ok, err := verify(items)
+	if err != nil || ok {
+		return invalid, err
+	}
+	if len(items) == 1 {
+		return append(invalid, items[0].index), nil
+	}
+	half := len(items) / 2
+	if invalid, err = findInvalidContributions(items[:half], verify, invalid); err != nil {
+		return nil, err
+	}
+	return findInvalidContributions(items[half:], verify, invalid)
+}
+
+// verifyBlameItems checks the partial signatures combined with fresh random
+// coefficients against the aggregated public key and the message hash
+func verifyBlameItems(items []blameItem, aggPub *bn256.G2, msgHash *bn256.G1) (bool, error) {
+	sig := new(bn256.G1).Set(&zeroG1)
+	pub := new(bn256.G2).Set(&zeroG2)
+	hash := new(bn256.G1).Set(&zeroG1)
+	for _, item := range items {
+		r, err := randomBatchScalar()
+		if err != nil {
+			return false, err
+		}
+		sig.Add(sig, new(bn256.G1).ScalarMult(item.sig, r))
+		pub.Add(pub, new(bn256.G2).ScalarMult(item.pub, r))
+		hash.Add(hash, new(bn256.G1).ScalarMult(item.hash, r))
+	}
+	a := []*bn256.G1{sig.Neg(sig), msgHash, hash}
+	b := []*bn256.G2{&g2, pub, aggPub}
+	return bn256.PairingCheck(a, b), nil
+}
+
+func aggregateContributions(pubs []PublicKey, contributions []Contribution) Multisig {
+	multi := NewZeroMultisig()
+	for _, contribution := range contributions {
+		multi.PartSignature = multi.PartSignature.Aggregate(contribution.Signature)
+		multi.PartPublicKey = multi.PartPublicKey.Aggregate(pubs[contribution.Index])
+		multi.PartMask.SetBit(multi.PartMask, int(contribution.Index), 1)
+	}
+	return multi
+}
+
+func containsIndex(indices []uint32, index uint32) bool {
+	for _, i := range indices {
+		if i == index {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package bls
+
+import (
+	"bytes"
+	"math/big"
+)
+
+// MultisigBuilder assembles the multisignature of the message from the
+// partial signatures of the group members, checking each one
+type MultisigBuilder struct {
+	suite   MultisigSuite
+	aggPub  PublicKey
+	pubs    []PublicKey
+	message []byte
+	multi   Multisig
+}
+
+// NewMultisigBuilder creates the builder of the multisignature of the
+// message by the group with the given public keys and their aggregated
+// public key, hashed by LegacyMultisigSuite. The aggregated public key must
+// be the one of AggregatePublicKeys with the anti-rogue coefficients of the
+// public keys, otherwise ErrAggregateMismatch is returned.
+func NewMultisigBuilder(aggPublicKey PublicKey, pubs []PublicKey, message []byte) (*MultisigBuilder, error) {
+	return LegacyMultisigSuite.NewMultisigBuilder(aggPublicKey, pubs, message)
+}
+
+// NewMultisigBuilder creates the builder of the multisignature of the
+// message hashed by the suite, see NewMultisigBuilder
+func (suite MultisigSuite) NewMultisigBuilder(aggPublicKey PublicKey, pubs []PublicKey, message []byte) (*MultisigBuilder, error) {
+	if err := aggPublicKey.validate(); err != nil {
+		return nil, err
+	}
+	if len(pubs) == 0 {
+		return nil, ErrNoKeys
+	}
+	for _, pub := range pubs {
+		if err := pub.validate(); err != nil {
+			return nil, err
+		}
+	}
+	expected := AggregatePublicKeys(pubs, CalculateAntiRogueCoefficients(pubs))
+	if !bytes.Equal(expected.Marshal(), aggPublicKey.Marshal()) {
+		return nil, ErrAggregateMismatch
+	}
+	return suite.newMultisigBuilder(aggPublicKey, append([]PublicKey{}, pubs...), message), nil
+}
+
+// newMultisigBuilder creates the builder trusting the aggregated public key,
+// e.g. the one computed by NewGroup
+func (suite MultisigSuite) newMultisigBuilder(aggPublicKey PublicKey, pubs []PublicKey, message []byte) *MultisigBuilder {
+	return &MultisigBuilder{
+		suite:   suite,
+		aggPub:  aggPublicKey,
+		pubs:    pubs,
+		message: message,
+		multi:   NewZeroMultisig(),
+	}
+}
+
+// Add checks the partial signature generated by Multisign of the member
+// with the given index and adds it to the multisignature
+func (builder *MultisigBuilder) Add(index uint32, sig Signature) error {
+	if int64(index) >= int64(len(builder.pubs)) {
+		return ErrIndexRange
+	}
+	if builder.multi.PartMask.Bit(int(index)) != 0 {
+		return ErrDuplicateSigner
+	}
+	if err := sig.validate(); err != nil {
+		return err
+	}
+	pub := builder.pubs[index]
+	if !builder.suite.VerifyMultisignPart(sig, builder.aggPub, pub, index, builder.message) {
+		return ErrInvalidSignature
+	}
+	builder.multi.PartSignature = builder.multi.PartSignature.Aggregate(sig)
+	builder.multi.PartPublicKey = builder.multi.PartPublicKey.Aggregate(pub)
+	builder.multi.PartMask.SetBit(builder.multi.PartMask, int(index), 1)
+	return nil
+}
+
+// Count returns the number of signers added
+func (builder *MultisigBuilder) Count() int {
+	return popCount(builder.multi.PartMask)
+}
+
+// Build returns the multisignature of the signers added so far
+func (builder *MultisigBuilder) Build() (Multisig, error) {
+	if builder.multi.PartMask.Sign() == 0 {
+		return Multisig{}, ErrInvalidMask
+	}
+	return Multisig{
+		PartSignature: builder.multi.PartSignature,
+		PartPublicKey: builder.multi.PartPublicKey,
+		PartMask:      new(big.Int).Set(builder.multi.PartMask),
+	}, nil
+}
+
+// VerifyMultisignPart checks the partial signature generated by Multisign of
+// the group member with the given index and public key by
+// LegacyMultisigSuite, see MultisigSuite.VerifyMultisignPart
+func (signature Signature) VerifyMultisignPart(aggPublicKey PublicKey, publicKey PublicKey, index uint32, message []byte) bool {
+	return LegacyMultisigSuite.VerifyMultisignPart(signature, aggPublicKey, publicKey, index, message)
+}
@@ -0,0 +1,164 @@
+package bls
+
+import (
+	"math/big"
+
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+// The checked variants below validate their arguments and return errors
+// instead of panicking on zero-value keys and signatures, which may come
+// from untrusted input (e.g. UnmarshalPublicKey(nil)).
+
+// SignChecked generates a simple BLS signature of the given message, see Sign
+func (secretKey PrivateKey) SignChecked(message []byte) (Signature, error) {
+	return LegacyCiphersuite.SignChecked(secretKey, message)
+}
+
+// SignChecked generates a simple BLS signature of the given message, see Sign
+func (cs Ciphersuite) SignChecked(secretKey PrivateKey, message []byte) (Signature, error) {
+	if err := secretKey.validate(); err != nil {
+		return Signature{}, err
+	}
+	return cs.Sign(secretKey, message), nil
+}
+
+// VerifyChecked checks the BLS signature of the message against the public
+// key of its signer. It returns ErrInvalidSignature if the check fails.
+func (signature Signature) VerifyChecked(publicKey PublicKey, message []byte) error {
+	return LegacyCiphersuite.VerifyChecked(publicKey, message, signature)
+}
+
+// VerifyChecked checks the BLS signature of the message against the public
+// key of its signer. It returns ErrInvalidSignature if the check fails.
+func (cs Ciphersuite) VerifyChecked(publicKey PublicKey, message []byte, signature Signature) error {
+	if err := publicKey.validate(); err != nil {
+		return err
+	}
+	if err := signature.validate(); err != nil {
+		return err
+	}
+	if !cs.Verify(publicKey, message, signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// MultisignChecked generates BLS multi-signature of the given message, see Multisign
+func (secretKey PrivateKey) MultisignChecked(message []byte, aggPublicKey PublicKey, membershipKey Signature) (Signature, error) {
+	if err := secretKey.validate(); err != nil {
+		return Signature{}, err
+	}
+	if err := aggPublicKey.validate(); err != nil {
+		return Signature{}, err
+	}
+	if err := membershipKey.validate(); err != nil {
+		return Signature{}, err
+	}
+	return secretKey.Multisign(message, aggPublicKey, membershipKey), nil
+}
+
+// VerifyChecked checks the BLS multisignature of the message against the
+// aggregated public key of all its signers, see Verify. It returns
+// ErrInvalidSignature if the check fails.
+func (multi Multisig) VerifyChecked(aggPublicKey PublicKey, message []byte) error {
+	return LegacyMultisigSuite.VerifyChecked(multi, aggPublicKey, message)
+}
+
+// AggregateChecked adds the given public keys, see Aggregate
+func (pub PublicKey) AggregateChecked(onemore PublicKey) (PublicKey, error) {
+	if pub.p == nil || onemore.p == nil {
+		return PublicKey{}, ErrNilKey
+	}
+	return pub.Aggregate(onemore), nil
+}
+
+// AggregateChecked adds the given signatures, see Aggregate
+func (signature Signature) AggregateChecked(onemore Signature) (Signature, error) {
+	if signature.p == nil || onemore.p == nil {
+		return Signature{}, ErrNilSignature
+	}
+	return signature.Aggregate(onemore), nil
+}
+
+// CalculateAntiRogueCoefficientsChecked returns the anti-rogue coefficients
+// of the public keys, see CalculateAntiRogueCoefficients
+func CalculateAntiRogueCoefficientsChecked(pubs []PublicKey) ([]big.Int, error) {
+	if len(pubs) == 0 {
+		return nil, ErrNoKeys
+	}
+	for _, pub := range pubs {
+		if err := pub.validate(); err != nil {
+			return nil, err
+		}
+	}
+	return CalculateAntiRogueCoefficients(pubs), nil
+}
+
+// AggregatePublicKeysChecked calculates P1*A1 + P2*A2 + ..., see AggregatePublicKeys
+func AggregatePublicKeysChecked(pubs []PublicKey, anticoefs []big.Int) (PublicKey, error) {
+	if len(pubs) == 0 {
+		return PublicKey{}, ErrNoKeys
+	}
+	if len(pubs) != len(anticoefs) {
+		return PublicKey{}, ErrLengthMismatch
+	}
+	for _, pub := range pubs {
+		if err := pub.validate(); err != nil {
+			return PublicKey{}, err
+		}
+	}
+	return AggregatePublicKeys(pubs, anticoefs), nil
+}
+
+// AggregateSignaturesChecked sums the given array of signatures, see AggregateSignatures
+func AggregateSignaturesChecked(sigs []Signature, anticoefs []big.Int) (Signature, error) {
+	if len(sigs) != len(anticoefs) {
+		return Signature{}, ErrLengthMismatch
+	}
+	for _, sig := range sigs {
+		if err := sig.validate(); err != nil {
+			return Signature{}, err
+		}
+	}
+	return AggregateSignatures(sigs, anticoefs), nil
+}
+
+func (secretKey PrivateKey) validate() error {
+	if secretKey.p == nil {
+		return ErrNilKey
+	}
+	if secretKey.p.Sign() <= 0 || secretKey.p.Cmp(bn256.Order) >= 0 {
+		return ErrScalarRange
+	}
+	return nil
+}
+
+// validate rejects zero-value public keys and the point at infinity, which
+// would verify the signature at infinity of any message
+func (pub PublicKey) validate() error {
+	if pub.p == nil {
+		return ErrNilKey
+	}
+	if isInfinity(pub.p.Marshal()) {
+		return ErrInfinity
+	}
+	return nil
+}
+
+func (signature Signature) validate() error {
+	if signature.p == nil {
+		return ErrNilSignature
+	}
+	return nil
+}
+
+// isInfinity checks whether the marshaled point is the point at infinity
+func isInfinity(raw []byte) bool {
+	for _, b := range raw {
+		if b != 0 {
+			return false
+		}
+	}
+	return true
+}
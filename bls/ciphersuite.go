@@ -0,0 +1,133 @@
+package bls
+
+import (
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+	"github.com/keep-network/keep-core/pkg/altbn128"
+)
+
+// Ciphersuite defines how messages are hashed to G1 by signing and
+// verification. Protocols using ciphersuites with different domain
+// separation tags can not replay signatures of each other.
+type Ciphersuite struct {
+	dst []byte // nil for the legacy hash without domain separation
+}
+
+var (
+	// LegacyCiphersuite hashes messages by try-and-increment without domain
+	// separation. It is used by Sign and Verify and is compatible with the
+	// Solidity verifier.
+	LegacyCiphersuite = Ciphersuite{}
+	// BasicCiphersuite is the RFC 9380 ciphersuite for plain signatures
+	BasicCiphersuite = NewCiphersuite("BLS_SIG_BN254G1_XMD:SHA-256_SVDW_RO_NUL_")
+	// AugmentedCiphersuite is the RFC 9380 ciphersuite for signatures of
+	// messages augmented with the public key of the signer
+	AugmentedCiphersuite = NewCiphersuite("BLS_SIG_BN254G1_XMD:SHA-256_SVDW_RO_AUG_")
+)
+
+// NewCiphersuite returns the ciphersuite hashing to G1 by RFC 9380
+// (BN254G1_XMD:SHA-256_SVDW_RO_) with the given domain separation tag
+func NewCiphersuite(dst string) Ciphersuite {
+	if dst == "" {
+		panic("bls: empty domain separation tag")
+	}
+	return Ciphersuite{dst: []byte(dst)}
+}
+
+// DST returns the domain separation tag, empty for LegacyCiphersuite
+func (cs Ciphersuite) DST() string {
+	return string(cs.dst)
+}
+
+func (cs Ciphersuite) hashToPoint(message []byte) *bn256.G1 {
+	if cs.dst == nil {
+		return altbn128.G1HashToPoint(message)
+	}
+	return hashToCurve(message, cs.dst)
+}
+
+// HashToPoint hashes the message to the point of G1 curve
+func (cs Ciphersuite) HashToPoint(message []byte) Signature {
+	return Signature{p: cs.hashToPoint(message)}
+}
+
+// Sign generates a simple BLS signature of the given message
+func (cs Ciphersuite) Sign(secretKey PrivateKey, message []byte) Signature {
+	return Signature{p: new(bn256.G1).ScalarMult(cs.hashToPoint(message), secretKey.p)}
+}
+
+// Verify checks the BLS signature of the message against the public key of
+// its signer. The public key at infinity is rejected, since the signature at
+// infinity would verify any message by it.
+func (cs Ciphersuite) Verify(publicKey PublicKey, message []byte, signature Signature) bool {
+	if publicKey.validate() != nil || signature.p == nil {
+		return false
+	}
+	a := []*bn256.G1{new(bn256.G1).Neg(signature.p), cs.hashToPoint(message)}
+	b := []*bn256.G2{&g2, publicKey.p}
+	return bn256.PairingCheck(a, b)
+}
+
+// SignAugmented generates a BLS signature of the message augmented with the
+// public key of the signer: sk×H(pk, m)
+func (cs Ciphersuite) SignAugmented(secretKey PrivateKey, message []byte) Signature {
+	pub := new(bn256.G2).ScalarBaseMult(secretKey.p)
+	return cs.Sign(secretKey, augment(pub, message))
+}
+
+// FastAggregateVerify checks the aggregated signature of the same message
+// by all the given public keys, see FastAggregateVerify
+func (cs Ciphersuite) FastAggregateVerify(pubs []PublicKey, message []byte, sig Signature) bool {
+	if len(pubs) == 0 {
+		return false
+	}
+	aggPub := new(bn256.G2).Set(&zeroG2)
+	for _, pub := range pubs {
+		if pub.p == nil {
+			return false
+		}
+		aggPub.Add(aggPub, pub.p)
+	}
+	return cs.Verify(PublicKey{p: aggPub}, message, sig)
+}
+
+// AggregateVerify checks the aggregated signature of distinct messages, see
+// AggregateVerify
+func (cs Ciphersuite) AggregateVerify(pubs []PublicKey, msgs [][]byte, sig Signature) bool {
+	if len(pubs) == 0 || len(pubs) != len(msgs) || !distinct(msgs) {
+		return false
+	}
+	points := make([]*bn256.G1, len(msgs))
+	for i, msg := range msgs {
+		points[i] = cs.hashToPoint(msg)
+	}
+	return aggregateVerify(pubs, points, sig)
+}
+
+// AggregateVerifyAugmented checks the aggregated signature of the messages
+// generated by SignAugmented
+func (cs Ciphersuite) AggregateVerifyAugmented(pubs []PublicKey, msgs [][]byte, sig Signature) bool {
+	if len(pubs) == 0 || len(pubs) != len(msgs) {
+		return false
+	}
+	points := make([]*bn256.G1, len(msgs))
+	for i, msg := range msgs {
+		if pubs[i].p == nil {
+			return false
+		}
+		points[i] = cs.hashToPoint(augment(pubs[i].p, msg))
+	}
+	return aggregateVerify(pubs, points, sig)
+}
+
+// BatchVerify checks many independent signatures at once, see BatchVerify
+func (cs Ciphersuite) BatchVerify(sets []SignatureSet) ([]int, error) {
+	return batchVerify(sets, cs.hashToPoint)
+}
+
+// augment prepends the public key to the message
+func augment(pub *bn256.G2, message []byte) []byte {
+	var data []byte
+	data = append(data, pub.Marshal()...)
+	data = append(data, message...)
+	return data
+}
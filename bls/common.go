@@ -5,7 +5,6 @@ import (
 	"math/big"
 
 	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
-	"github.com/keep-network/keep-core/pkg/altbn128"
 )
 
 var (
@@ -27,24 +26,8 @@ func ZeroMultisigMask() *big.Int {
 	return big.NewInt(0)
 }
 
-// hashToPointMsg performs "message augmentation": hashes the message and the
-// point to the point of G1 curve (a signature)
-func hashToPointMsg(p *bn256.G2, message []byte) *bn256.G1 {
-	var data []byte
-	data = append(data, p.Marshal()...)
-	data = append(data, message...)
-	return altbn128.G1HashToPoint(data)
-}
-
-// hashToPointIndex hashes the aggregated public key (G2 point) and the given
-// index (of the signer within a group of signers) to the point in G1 curve (a
-// signature)
-func hashToPointIndex(pub *bn256.G2, index byte) *bn256.G1 {
-	data := make([]byte, 32)
-	data[31] = index
-	return hashToPointMsg(pub, data)
-}
-
-func HashToPointIndex(pub PublicKey, index byte) Signature {
-	return Signature{p: hashToPointIndex(pub.p, index)}
+// HashToPointIndex hashes the aggregated public key and the index to G1 by
+// LegacyMultisigSuite
+func HashToPointIndex(pub PublicKey, index uint32) Signature {
+	return LegacyMultisigSuite.HashToPointIndex(pub, index)
 }
@@ -0,0 +1,180 @@
+package bls
+
+import (
+	"math/big"
+
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+const (
+	CompressedSignatureSize = 32 // G1 point: x with flags
+	CompressedPublicKeySize = 64 // G2 point: x.im with flags, x.re
+)
+
+// The top two bits of a compressed point are flags, as in gnark-crypto:
+// the field elements are less than 2^254, so the bits are free.
+const (
+	flagMask         = 0b11 << 6
+	flagSmallest     = 0b10 << 6 // y is lexicographically smallest of ±y
+	flagLargest      = 0b11 << 6 // y is lexicographically largest of ±y
+	flagInfinity     = 0b01 << 6
+	flagUncompressed = 0b00 << 6 // uncompressed, invalid in the compressed form
+)
+
+// MarshalCompressed encodes the signature as the x coordinate with the
+// flags of y and the point at infinity in the top bits
+func (signature Signature) MarshalCompressed() []byte {
+	if signature.p == nil {
+		return nil
+	}
+	raw := signature.p.Marshal()
+	res := make([]byte, CompressedSignatureSize)
+	if isInfinity(raw) {
+		res[0] = flagInfinity
+		return res
+	}
+	copy(res, raw[:32])
+	res[0] |= ySign(new(big.Int).SetBytes(raw[32:]))
+	return res
+}
+
+// MarshalCompressed encodes the public key as the x coordinate with the
+// flags of y and the point at infinity in the top bits
+func (pub PublicKey) MarshalCompressed() []byte {
+	if pub.p == nil {
+		return nil
+	}
+	raw := pub.p.Marshal()
+	res := make([]byte, CompressedPublicKeySize)
+	if isInfinity(raw) {
+		res[0] = flagInfinity
+		return res
+	}
+	copy(res, raw[:64])
+	yIm, yRe := new(big.Int).SetBytes(raw[64:96]), new(big.Int).SetBytes(raw[96:])
+	if yIm.Sign() == 0 {
+		res[0] |= ySign(yRe)
+	} else {
+		res[0] |= ySign(yIm)
+	}
+	return res
+}
+
+// UnmarshalCompressedSignature decodes the signature encoded by
+// Signature.MarshalCompressed. The x coordinate must be canonical and the
+// point must be on the curve.
+func UnmarshalCompressedSignature(raw []byte) (Signature, error) {
+	if len(raw) != CompressedSignatureSize {
+		return Signature{}, ErrInvalidLength
+	}
+	flags, x, err := readCompressed(raw)
+	if err != nil {
+		return Signature{}, err
+	}
+	data := make([]byte, SignatureSize)
+	if flags != flagInfinity {
+		y := fpSqrt(curveG(x[0]))
+		if fpMul(y, y).Cmp(curveG(x[0])) != 0 {
+			return Signature{}, ErrNotOnCurve
+		}
+		if ySign(y) != flags {
+			y = fpNeg(y)
+		}
+		x[0].FillBytes(data[:32])
+		y.FillBytes(data[32:])
+	}
+	p := new(bn256.G1)
+	if _, err := p.Unmarshal(data); err != nil {
+		return Signature{}, ErrNotOnCurve
+	}
+	return Signature{p: p}, nil
+}
+
+// UnmarshalCompressedPublicKey decodes the public key encoded by
+// PublicKey.MarshalCompressed. The x coordinate must be canonical and the
+// point must be in G2.
+func UnmarshalCompressedPublicKey(raw []byte) (PublicKey, error) {
+	if len(raw) != CompressedPublicKeySize {
+		return PublicKey{}, ErrInvalidLength
+	}
+	flags, coords, err := readCompressed(raw)
+	if err != nil {
+		return PublicKey{}, err
+	}
+	data := make([]byte, PublicKeySize)
+	if flags != flagInfinity {
+		x := fp2{re: coords[1], im: coords[0]}
+		y, ok := fp2Sqrt(x.mul(x).mul(x).add(twistB))
+		if !ok {
+			return PublicKey{}, ErrNotOnCurve
+		}
+		sign := ySign(y.im)
+		if y.im.Sign() == 0 {
+			sign = ySign(y.re)
+		}
+		if sign != flags {
+			y = fp2{re: fpNeg(y.re), im: fpNeg(y.im)}
+		}
+		x.im.FillBytes(data[:32])
+		x.re.FillBytes(data[32:64])
+		y.im.FillBytes(data[64:96])
+		y.re.FillBytes(data[96:])
+	}
+	p := new(bn256.G2)
+	if _, err := p.Unmarshal(data); err != nil {
+		// The point is on the curve, bn256 rejects it as out of G2
+		return PublicKey{}, ErrNotInSubgroup
+	}
+	return PublicKey{p: p}, nil
+}
+
+// readCompressed reads the flags and the 32-byte field elements of the
+// compressed point
+func readCompressed(raw []byte) (byte, []*big.Int, error) {
+	flags := raw[0] & flagMask
+	data := append([]byte{}, raw...)
+	data[0] &^= flagMask
+	switch flags {
+	case flagUncompressed:
+		return 0, nil, ErrNonCanonical
+	case flagInfinity:
+		if !isInfinity(data) {
+			return 0, nil, ErrNonCanonical
+		}
+		return flags, nil, nil
+	}
+	coords := make([]*big.Int, len(data)/32)
+	for i := range coords {
+		var ok bool
+		if coords[i], ok = readFp(data[i*32 : (i+1)*32]); !ok {
+			return 0, nil, ErrNonCanonical
+		}
+	}
+	return flags, coords, nil
+}
+
+// ySign returns the flag of the field element: largest if y > (p-1)/2
+func ySign(y *big.Int) byte {
+	if y.Cmp(pMinus1Over2) > 0 {
+		return flagLargest
+	}
+	return flagSmallest
+}
+
+// unmarshalPublicKey reads the compressed or uncompressed public key by the
+// length of the encoding
+func unmarshalPublicKey(raw []byte) (PublicKey, error) {
+	if len(raw) == CompressedPublicKeySize {
+		return UnmarshalCompressedPublicKey(raw)
+	}
+	return UnmarshalPublicKey(raw)
+}
+
+// unmarshalSignature reads the compressed or uncompressed signature by the
+// length of the encoding
+func unmarshalSignature(raw []byte) (Signature, error) {
+	if len(raw) == CompressedSignatureSize {
+		return UnmarshalCompressedSignature(raw)
+	}
+	return UnmarshalSignature(raw)
+}
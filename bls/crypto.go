@@ -5,27 +5,145 @@ import (
 	"crypto/cipher"
 	"crypto/md5"
 	"crypto/rand"
+	"encoding/binary"
 	"encoding/hex"
+	"errors"
 	"io"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	envelopeVersion1   = 0x01 // scrypt + AES-256-GCM
+	envelopeSaltSize   = 32
+	envelopeNonceSize  = 12
+	envelopeHeaderSize = 1 + 1 + 2 + 2 + envelopeSaltSize + envelopeNonceSize
+	envelopeKeySize    = 32
+)
+
+// Limits of the scrypt parameters, which are read from the untrusted
+// envelope header: at most 1GB of memory and r⋅p = 64
+const (
+	maxScryptLogN   = 20
+	maxScryptMemory = 1 << 30 // 128⋅r⋅N bytes
+	maxScryptRP     = 64
+)
+
+// ScryptParams are the tunable cost parameters of the scrypt key derivation
+// used to protect encrypted private keys: N = 2^LogN, r = R, p = P
+type ScryptParams struct {
+	LogN uint8
+	R    uint16
+	P    uint16
+}
+
+var (
+	// StandardScryptParams is the default cost (256MB of memory, ~1s on a modern CPU)
+	StandardScryptParams = ScryptParams{LogN: 18, R: 8, P: 1}
+	// LightScryptParams is a low cost for tests and resource-constrained devices
+	LightScryptParams = ScryptParams{LogN: 12, R: 8, P: 6}
 )
 
-// Encrypt - encrypts a BLS PrivateKey with a given passphrase
+var errMalformedEnvelope = errors.New("bls: malformed encrypted key")
+
+// Encrypt - encrypts a BLS PrivateKey with a given passphrase using the
+// standard scrypt parameters
 func (blsKey *PrivateKey) Encrypt(passphrase string) (string, error) {
-	block, _ := aes.NewCipher([]byte(createHash(passphrase)))
+	return blsKey.EncryptWithParams(passphrase, StandardScryptParams)
+}
 
-	gcm, err := cipher.NewGCM(block)
+// EncryptWithParams encrypts a BLS PrivateKey with a given passphrase. The
+// result is a hex encoded envelope:
+//
+// version | log2(N) | r | p | salt | nonce | AES-GCM ciphertext
+//
+// The header is authenticated along with the ciphertext.
+func (blsKey *PrivateKey) EncryptWithParams(passphrase string, params ScryptParams) (string, error) {
+	encrypted, err := encryptEnvelope(blsKey.Marshal(), passphrase, params)
 	if err != nil {
 		return "", err
 	}
+	return hex.EncodeToString(encrypted), nil
+}
 
-	nonce := make([]byte, gcm.NonceSize())
-	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+// Reencrypt decrypts the key encrypted in any supported format (including the
+// legacy MD5-based one) and encrypts it again using the given scrypt parameters
+func Reencrypt(encrypted []byte, passphrase string, params ScryptParams) (string, error) {
+	plaintext, err := Decrypt(encrypted, passphrase)
+	if err != nil {
 		return "", err
 	}
+	reencrypted, err := encryptEnvelope(plaintext, passphrase, params)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(reencrypted), nil
+}
 
-	ciphertext := gcm.Seal(nonce, nonce, []byte(blsKey.Marshal()), nil)
+func encryptEnvelope(plaintext []byte, passphrase string, params ScryptParams) ([]byte, error) {
+	header := make([]byte, envelopeHeaderSize)
+	header[0] = envelopeVersion1
+	header[1] = params.LogN
+	binary.BigEndian.PutUint16(header[2:4], params.R)
+	binary.BigEndian.PutUint16(header[4:6], params.P)
+	salt := header[6 : 6+envelopeSaltSize]
+	nonce := header[6+envelopeSaltSize:]
+	if _, err := io.ReadFull(rand.Reader, header[6:]); err != nil {
+		return nil, err
+	}
 
-	return hex.EncodeToString(ciphertext), nil
+	gcm, err := newEnvelopeCipher(passphrase, salt, params)
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Seal(header, nonce, plaintext, header), nil
+}
+
+func decryptEnvelope(data []byte, passphrase string) ([]byte, error) {
+	if len(data) < envelopeHeaderSize || data[0] != envelopeVersion1 {
+		return nil, errMalformedEnvelope
+	}
+	params := ScryptParams{
+		LogN: data[1],
+		R:    binary.BigEndian.Uint16(data[2:4]),
+		P:    binary.BigEndian.Uint16(data[4:6]),
+	}
+	header := data[:envelopeHeaderSize]
+	salt := header[6 : 6+envelopeSaltSize]
+	nonce := header[6+envelopeSaltSize:]
+
+	gcm, err := newEnvelopeCipher(passphrase, salt, params)
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Open(nil, nonce, data[envelopeHeaderSize:], header)
+}
+
+func newEnvelopeCipher(passphrase string, salt []byte, params ScryptParams) (cipher.AEAD, error) {
+	if err := params.validate(); err != nil {
+		return nil, err
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, 1<<params.LogN, int(params.R), int(params.P), envelopeKeySize)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// validate checks the parameters against the limits, so that a crafted
+// envelope can not exhaust the memory or the CPU
+func (params ScryptParams) validate() error {
+	if params.LogN == 0 || params.LogN > maxScryptLogN || params.R == 0 || params.P == 0 {
+		return ErrScryptParams
+	}
+	if int(params.R)*int(params.P) > maxScryptRP || 128*int64(params.R)<<params.LogN > maxScryptMemory {
+		return ErrScryptParams
+	}
+	return nil
 }
 
 func createHash(key string) string {
@@ -34,15 +152,17 @@ func createHash(key string) string {
 	return hex.EncodeToString(hasher.Sum(nil))
 }
 
+// Decrypt decrypts the private key encrypted either by Encrypt or by the
+// legacy MD5-based scheme, in hex or binary form
 func Decrypt(encrypted []byte, passphrase string) (decrypted []byte, err error) {
 	unhexed := make([]byte, hex.DecodedLen(len(encrypted)))
 	if _, err = hex.Decode(unhexed, encrypted); err == nil {
-		if decrypted, err = decryptRaw(unhexed, passphrase); err == nil {
+		if decrypted, err = decryptAny(unhexed, passphrase); err == nil {
 			return decrypted, nil
 		}
 	}
-	// At this point err != nil, either from hex decode or from decryptRaw.
-	decrypted, binErr := decryptRaw(encrypted, passphrase)
+	// At this point err != nil, either from hex decode or from decryptAny.
+	decrypted, binErr := decryptAny(encrypted, passphrase)
 	if binErr != nil {
 		// Disregard binary decryption error and return the original error,
 		// because our canonical form is hex and not binary.
@@ -51,6 +171,24 @@ func Decrypt(encrypted []byte, passphrase string) (decrypted []byte, err error)
 	return decrypted, nil
 }
 
+// decryptAny tries the versioned envelope first and falls back to the legacy
+// format, whose random nonce may happen to start with a version byte
+func decryptAny(data []byte, passphrase string) ([]byte, error) {
+	if len(data) > 0 && data[0] == envelopeVersion1 {
+		plaintext, err := decryptEnvelope(data, passphrase)
+		if err == nil {
+			return plaintext, nil
+		}
+		if plaintext, legacyErr := decryptRaw(data, passphrase); legacyErr == nil {
+			return plaintext, nil
+		}
+		return nil, err
+	}
+	return decryptRaw(data, passphrase)
+}
+
+// decryptRaw decrypts the legacy format: AES-GCM keyed by unsalted MD5 of
+// the passphrase
 func decryptRaw(data []byte, passphrase string) ([]byte, error) {
 	var err error
 	key := []byte(createHash(passphrase))
@@ -63,6 +201,9 @@ func decryptRaw(data []byte, passphrase string) ([]byte, error) {
 		return nil, err
 	}
 	nonceSize := gcm.NonceSize()
+	if len(data) < nonceSize {
+		return nil, errMalformedEnvelope
+	}
 	nonce, ciphertext := data[:nonceSize], data[nonceSize:]
 	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
 	return plaintext, err
@@ -0,0 +1,124 @@
+package bls
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+	"golang.org/x/crypto/hkdf"
+)
+
+// Hierarchical deterministic key derivation following EIP-2333, with the
+// BLS12-381 group order replaced by the order of bn256. All derivations are
+// hardened: child keys can not be derived from the parent public key.
+
+const (
+	hkdfModROutputSize = 48 // ceil(3 * ceil(log2(r)) / 16)
+	lamportChunks      = 255
+	lamportChunkSize   = sha256.Size
+)
+
+var hkdfModRSalt = []byte("BLS-SIG-KEYGEN-SALT-")
+
+// DeriveMasterKey derives the master private key from the seed, which must
+// be at least 32 bytes long
+func DeriveMasterKey(seed []byte) (PrivateKey, error) {
+	if len(seed) < 32 {
+		return PrivateKey{}, errors.New("bls: seed must be at least 32 bytes")
+	}
+	return PrivateKey{p: hkdfModR(seed, bn256.Order)}, nil
+}
+
+// DeriveChild derives the child private key with the given index
+func (secretKey PrivateKey) DeriveChild(index uint32) (PrivateKey, error) {
+	if secretKey.p == nil {
+		return PrivateKey{}, ErrNilKey
+	}
+	lamportPK := parentToLamportPK(secretKey.p, index)
+	return PrivateKey{p: hkdfModR(lamportPK, bn256.Order)}, nil
+}
+
+// DeriveKey derives the private key from the seed by the path of the form
+// "m/12381/60/0/0"
+func DeriveKey(seed []byte, path string) (PrivateKey, error) {
+	indices, err := ParseDerivationPath(path)
+	if err != nil {
+		return PrivateKey{}, err
+	}
+	key, err := DeriveMasterKey(seed)
+	if err != nil {
+		return PrivateKey{}, err
+	}
+	for _, index := range indices {
+		if key, err = key.DeriveChild(index); err != nil {
+			return PrivateKey{}, err
+		}
+	}
+	return key, nil
+}
+
+// ParseDerivationPath parses the path of the form "m/12381/60/0/0" into the
+// list of child indices
+func ParseDerivationPath(path string) ([]uint32, error) {
+	parts := strings.Split(path, "/")
+	if parts[0] != "m" {
+		return nil, errors.New("bls: derivation path must start with m")
+	}
+	indices := make([]uint32, 0, len(parts)-1)
+	for _, part := range parts[1:] {
+		index, err := strconv.ParseUint(part, 10, 32)
+		if err != nil {
+			return nil, errors.New("bls: invalid derivation path index " + strconv.Quote(part))
+		}
+		indices = append(indices, uint32(index))
+	}
+	return indices, nil
+}
+
+// hkdfModR derives a non-zero scalar modulo the order from the key material
+func hkdfModR(ikm []byte, order *big.Int) *big.Int {
+	salt := hkdfModRSalt
+	info := []byte{0, hkdfModROutputSize} // key_info || I2OSP(L, 2)
+	ikm = append(append([]byte{}, ikm...), 0)
+	sk := new(big.Int)
+	for sk.Sign() == 0 {
+		hash := sha256.Sum256(salt)
+		salt = hash[:]
+		okm := make([]byte, hkdfModROutputSize)
+		if _, err := io.ReadFull(hkdf.New(sha256.New, ikm, salt, info), okm); err != nil {
+			panic(err)
+		}
+		sk.SetBytes(okm).Mod(sk, order)
+	}
+	return sk
+}
+
+// parentToLamportPK calculates the compressed Lamport public key used as
+// the key material of the child
+func parentToLamportPK(parent *big.Int, index uint32) []byte {
+	salt := make([]byte, 4)
+	binary.BigEndian.PutUint32(salt, index)
+	ikm := parent.FillBytes(make([]byte, 32))
+	notIkm := make([]byte, len(ikm))
+	for i, b := range ikm {
+		notIkm[i] = ^b
+	}
+
+	hasher := sha256.New()
+	for _, key := range [][]byte{ikm, notIkm} {
+		lamportSK := make([]byte, lamportChunks*lamportChunkSize)
+		if _, err := io.ReadFull(hkdf.New(sha256.New, key, salt, nil), lamportSK); err != nil {
+			panic(err)
+		}
+		for i := 0; i < lamportChunks; i++ {
+			chunk := sha256.Sum256(lamportSK[i*lamportChunkSize : (i+1)*lamportChunkSize])
+			hasher.Write(chunk[:])
+		}
+	}
+	return hasher.Sum(nil)
+}
@@ -0,0 +1,84 @@
+package bls
+
+import "errors"
+
+var (
+	// ErrLengthMismatch is returned when the lengths of the corresponding
+	// arrays of keys, messages, signatures or coefficients differ
+	ErrLengthMismatch = errors.New("bls: length mismatch")
+	// ErrDuplicateMessage is returned when aggregating signatures of the same
+	// message without message augmentation
+	ErrDuplicateMessage = errors.New("bls: duplicate message")
+)
+
+var (
+	// ErrInvalidLength is returned when the encoding has the wrong size
+	ErrInvalidLength = errors.New("bls: invalid encoding length")
+	// ErrNonCanonical is returned when a coordinate is not reduced modulo p
+	ErrNonCanonical = errors.New("bls: non-canonical encoding")
+	// ErrInfinity is returned for the point at infinity (identity element)
+	ErrInfinity = errors.New("bls: point at infinity")
+	// ErrNotOnCurve is returned when the point does not satisfy the curve equation
+	ErrNotOnCurve = errors.New("bls: point is not on curve")
+	// ErrNotInSubgroup is returned when the point is on the curve but out of G2
+	ErrNotInSubgroup = errors.New("bls: point is not in subgroup")
+	// ErrScalarRange is returned when the private key is zero or not less
+	// than the group order
+	ErrScalarRange = errors.New("bls: private key out of range")
+)
+
+var (
+	// ErrNilKey is returned for a zero-value private or public key
+	ErrNilKey = errors.New("bls: nil key")
+	// ErrNilSignature is returned for a zero-value signature
+	ErrNilSignature = errors.New("bls: nil signature")
+	// ErrInvalidMask is returned for a nil, zero or negative multisig mask
+	ErrInvalidMask = errors.New("bls: invalid multisig mask")
+	// ErrNoKeys is returned when aggregating an empty set of public keys
+	ErrNoKeys = errors.New("bls: no public keys")
+	// ErrInvalidSignature is returned when the signature does not verify
+	ErrInvalidSignature = errors.New("bls: invalid signature")
+)
+
+var (
+	// ErrIndexRange is returned when the signer index is out of the group
+	ErrIndexRange = errors.New("bls: signer index out of range")
+	// ErrDuplicateSigner is returned when the signer has already signed
+	ErrDuplicateSigner = errors.New("bls: duplicate signer")
+	// ErrAggregateMismatch is returned when the aggregated public key is not
+	// the one of the public keys of the group
+	ErrAggregateMismatch = errors.New("bls: aggregated public key mismatch")
+)
+
+var (
+	// ErrInvalidWeight is returned for a nil or negative member weight
+	ErrInvalidWeight = errors.New("bls: invalid weight")
+	// ErrInsufficientWeight is returned when the signed weight is below the threshold
+	ErrInsufficientWeight = errors.New("bls: insufficient signed weight")
+)
+
+var (
+	// ErrNotMember is returned when the public key is not in the group
+	ErrNotMember = errors.New("bls: not a group member")
+	// ErrDuplicateMember is returned when the group has the same public key twice
+	ErrDuplicateMember = errors.New("bls: duplicate group member")
+	// ErrGroupID is returned when the serialized group ID does not match its members
+	ErrGroupID = errors.New("bls: group id mismatch")
+)
+
+var (
+	// ErrUnsupportedVersion is returned when decoding an unknown version of
+	// the binary encoding
+	ErrUnsupportedVersion = errors.New("bls: unsupported encoding version")
+)
+
+var (
+	// ErrRedacted is returned when reading the redacted encoding of a private key
+	ErrRedacted = errors.New("bls: redacted private key")
+)
+
+var (
+	// ErrScryptParams is returned when the scrypt parameters of an encrypted
+	// key exceed the limits
+	ErrScryptParams = errors.New("bls: scrypt parameters out of range")
+)
@@ -0,0 +1,68 @@
+package bls
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// redacted replaces the private key in the default encodings and formatting
+const redacted = "REDACTED"
+
+// String returns the redacted private key
+func (secretKey PrivateKey) String() string {
+	return "bls.PrivateKey(" + redacted + ")"
+}
+
+// GoString returns the redacted private key for the %#v format
+func (secretKey PrivateKey) GoString() string {
+	return "bls.PrivateKey{" + redacted + "}"
+}
+
+// Format writes the redacted private key for every verb, so that neither
+// %x nor %d prints the scalar
+func (secretKey PrivateKey) Format(f fmt.State, verb rune) {
+	if verb == 'v' && f.Flag('#') {
+		fmt.Fprint(f, secretKey.GoString())
+		return
+	}
+	fmt.Fprint(f, secretKey.String())
+}
+
+// ExportedPrivateKey is the private key encoded in plaintext by JSON and
+// text marshaling, unlike PrivateKey. Convert the key explicitly where it is
+// to be exported:
+//
+//	data, err := json.Marshal(bls.ExportedPrivateKey(secretKey))
+type ExportedPrivateKey PrivateKey
+
+// PrivateKey returns the exported private key
+func (exported ExportedPrivateKey) PrivateKey() PrivateKey {
+	return PrivateKey(exported)
+}
+
+// MarshalText encodes the private key as 64 hex digits of PrivateKey.Marshal
+func (exported ExportedPrivateKey) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(PrivateKey(exported).Marshal())), nil
+}
+
+// UnmarshalText reads the private key encoded by MarshalText
+func (exported *ExportedPrivateKey) UnmarshalText(text []byte) error {
+	return (*PrivateKey)(exported).UnmarshalText(text)
+}
+
+// MarshalJSON encodes the private key as the string of MarshalText, null
+// for the empty key
+func (exported ExportedPrivateKey) MarshalJSON() ([]byte, error) {
+	if exported.p == nil {
+		return json.Marshal(nil)
+	}
+	text, _ := exported.MarshalText()
+	return json.Marshal(string(text))
+}
+
+// UnmarshalJSON reads the private key encoded by MarshalJSON or the decimal
+// number of the former encoding
+func (exported *ExportedPrivateKey) UnmarshalJSON(data []byte) error {
+	return (*PrivateKey)(exported).UnmarshalJSON(data)
+}
@@ -0,0 +1,90 @@
+package bls
+
+import (
+	"math/big"
+
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+// fp2 is an element re + im⋅i of Fp² = Fp[i]/(i² + 1), the field of the
+// coordinates of G2 points. Marshaled G2 points store the imaginary part
+// first.
+type fp2 struct {
+	re, im *big.Int
+}
+
+var (
+	// twistB is the coefficient of the twist curve y² = x³ + 3/(9 + i)
+	twistB = fp2Inv(fp2{re: big.NewInt(9), im: big.NewInt(1)}).mulScalar(big.NewInt(3))
+
+	fp2One       = fp2{re: big.NewInt(1), im: big.NewInt(0)}
+	fp2MinusOne  = fp2{re: fpNeg(big.NewInt(1)), im: big.NewInt(0)}
+	pMinus3Over4 = new(big.Int).Rsh(new(big.Int).Sub(bn256.P, big.NewInt(3)), 2)
+)
+
+func (a fp2) add(b fp2) fp2 {
+	return fp2{re: fpAdd(a.re, b.re), im: fpAdd(a.im, b.im)}
+}
+
+func (a fp2) mul(b fp2) fp2 {
+	return fp2{
+		re: fpSub(fpMul(a.re, b.re), fpMul(a.im, b.im)),
+		im: fpAdd(fpMul(a.re, b.im), fpMul(a.im, b.re)),
+	}
+}
+
+func (a fp2) mulScalar(k *big.Int) fp2 {
+	return fp2{re: fpMul(a.re, k), im: fpMul(a.im, k)}
+}
+
+func (a fp2) equal(b fp2) bool {
+	return a.re.Cmp(b.re) == 0 && a.im.Cmp(b.im) == 0
+}
+
+func (a fp2) conj() fp2 {
+	return fp2{re: a.re, im: fpNeg(a.im)}
+}
+
+func (a fp2) exp(e *big.Int) fp2 {
+	res := fp2One
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		res = res.mul(res)
+		if e.Bit(i) == 1 {
+			res = res.mul(a)
+		}
+	}
+	return res
+}
+
+func fp2Inv(a fp2) fp2 {
+	norm := fpInv0(fpAdd(fpMul(a.re, a.re), fpMul(a.im, a.im)))
+	return fp2{re: fpMul(a.re, norm), im: fpNeg(fpMul(a.im, norm))}
+}
+
+// fp2Sqrt returns a square root of a, or false if a is not a square. It is
+// the algorithm 9 of "Square root computation over even extension fields"
+// by Adj and Rodríguez-Henríquez for p = 3 mod 4.
+func fp2Sqrt(a fp2) (fp2, bool) {
+	a1 := a.exp(pMinus3Over4)
+	alpha := a1.mul(a1).mul(a)
+	if alpha.conj().mul(alpha).equal(fp2MinusOne) {
+		return fp2{}, false
+	}
+	x0 := a1.mul(a)
+	if alpha.equal(fp2MinusOne) {
+		return fp2{re: fpNeg(x0.im), im: x0.re}, true // i⋅x0
+	}
+	b := alpha.add(fp2One).exp(pMinus1Over2)
+	return b.mul(x0), true
+}
+
+// isOnTwist checks the affine point against the twist curve equation
+func isOnTwist(x, y fp2) bool {
+	return y.mul(y).equal(x.mul(x).mul(x).add(twistB))
+}
+
+// readFp reads a big-endian field element, which must be less than p
+func readFp(data []byte) (*big.Int, bool) {
+	res := new(big.Int).SetBytes(data)
+	return res, res.Cmp(bn256.P) < 0
+}
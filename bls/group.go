@@ -0,0 +1,230 @@
+package bls
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+)
+
+// Group is the ordered set of members of accountable multisignatures with
+// their anti-rogue coefficients and the aggregated public key:
+//
+// P = A1×pk1 + A2×pk2 + ...
+type Group struct {
+	pubs    []PublicKey
+	coefs   []big.Int
+	aggPub  PublicKey
+	id      [32]byte
+	indices map[string]uint32 // by marshaled public key
+	suite   MultisigSuite
+}
+
+// NewGroup creates the group of the public keys, the index of a member is
+// its position in the array. Its multisignatures are hashed by
+// LegacyMultisigSuite.
+func NewGroup(pubs []PublicKey) (*Group, error) {
+	coefs, err := CalculateAntiRogueCoefficientsChecked(pubs)
+	if err != nil {
+		return nil, err
+	}
+	aggPub, err := AggregatePublicKeysChecked(pubs, coefs)
+	if err != nil {
+		return nil, err
+	}
+	group := &Group{
+		pubs:    append([]PublicKey{}, pubs...),
+		coefs:   coefs,
+		aggPub:  aggPub,
+		indices: make(map[string]uint32, len(pubs)),
+		suite:   LegacyMultisigSuite,
+	}
+	hasher := sha256.New()
+	for i, pub := range pubs {
+		raw := pub.Marshal()
+		if _, ok := group.indices[string(raw)]; ok {
+			return nil, ErrDuplicateMember
+		}
+		group.indices[string(raw)] = uint32(i)
+		hasher.Write(raw)
+	}
+	copy(group.id[:], hasher.Sum(nil))
+	return group, nil
+}
+
+// WithSuite returns the copy of the group hashing its multisignatures by
+// the suite, e.g. SeparatedMultisigSuite for the groups verified off-chain
+func (group *Group) WithSuite(suite MultisigSuite) *Group {
+	res := *group
+	res.suite = suite
+	return &res
+}
+
+// Suite returns the suite hashing the multisignatures of the group
+func (group *Group) Suite() MultisigSuite {
+	return group.suite
+}
+
+// ID returns the hash of the public keys of the members identifying the group
+func (group *Group) ID() [32]byte {
+	return group.id
+}
+
+// Size returns the number of members
+func (group *Group) Size() int {
+	return len(group.pubs)
+}
+
+// PublicKeys returns the public keys of the members
+func (group *Group) PublicKeys() []PublicKey {
+	return append([]PublicKey{}, group.pubs...)
+}
+
+// PublicKey returns the public key of the member with the given index
+func (group *Group) PublicKey(index uint32) (PublicKey, error) {
+	if int64(index) >= int64(len(group.pubs)) {
+		return PublicKey{}, ErrIndexRange
+	}
+	return group.pubs[index], nil
+}
+
+// AntiRogueCoefficients returns the anti-rogue coefficients of the members
+func (group *Group) AntiRogueCoefficients() []big.Int {
+	return append([]big.Int{}, group.coefs...)
+}
+
+// AggregatedPublicKey returns the aggregated public key of the group
+func (group *Group) AggregatedPublicKey() PublicKey {
+	return group.aggPub
+}
+
+// IndexOf returns the index of the member with the public key
+func (group *Group) IndexOf(pub PublicKey) (uint32, bool) {
+	index, ok := group.indices[string(pub.Marshal())]
+	return index, ok
+}
+
+// MembershipKeyPart generates the part of the membership key of the member
+// with the given index by the member with the private key
+func (group *Group) MembershipKeyPart(secretKey PrivateKey, index uint32) (Signature, error) {
+	if err := secretKey.validate(); err != nil {
+		return Signature{}, err
+	}
+	if int64(index) >= int64(len(group.pubs)) {
+		return Signature{}, ErrIndexRange
+	}
+	sender, ok := group.IndexOf(secretKey.PublicKey())
+	if !ok {
+		return Signature{}, ErrNotMember
+	}
+	return group.suite.GenerateMembershipKeyPart(secretKey, index, group.aggPub, group.coefs[sender]), nil
+}
+
+// VerifyMembershipKeyPart checks the part of the membership key of the
+// recipient generated by the sender
+func (group *Group) VerifyMembershipKeyPart(part Signature, sender, recipient uint32) bool {
+	if int64(sender) >= int64(len(group.pubs)) {
+		return false
+	}
+	return group.suite.VerifyMembershipKeyPart(part, group.aggPub, group.pubs[sender], group.coefs[sender], recipient)
+}
+
+// NewMultisigBuilder creates the builder of the multisignature of the
+// message by the group
+func (group *Group) NewMultisigBuilder(message []byte) (*MultisigBuilder, error) {
+	return group.suite.newMultisigBuilder(group.aggPub, group.pubs, message), nil
+}
+
+// Verify checks the multisignature of the message by the members of the group
+func (group *Group) Verify(multi Multisig, message []byte) error {
+	if multi.PartMask != nil && multi.PartMask.BitLen() > len(group.pubs) {
+		return ErrIndexRange
+	}
+	return group.suite.VerifyChecked(multi, group.aggPub, message)
+}
+
+// maxSuiteTagSize is the limit of the suite tag in the encodings of groups
+const maxSuiteTagSize = 255
+
+// MarshalBinary encodes the group as the number of members (4 bytes,
+// big-endian) followed by their public keys and the tag of its suite
+// prefixed by its length (1 byte)
+func (group Group) MarshalBinary() ([]byte, error) {
+	if len(group.suite.tag) > maxSuiteTagSize {
+		return nil, ErrInvalidLength
+	}
+	res := make([]byte, 4, 4+len(group.pubs)*PublicKeySize+1+len(group.suite.tag))
+	binary.BigEndian.PutUint32(res, uint32(len(group.pubs)))
+	for _, pub := range group.pubs {
+		res = append(res, pub.Marshal()...)
+	}
+	res = append(res, byte(len(group.suite.tag)))
+	return append(res, group.suite.tag...), nil
+}
+
+// UnmarshalBinary decodes the group encoded by MarshalBinary
+func (group *Group) UnmarshalBinary(data []byte) error {
+	if len(data) < 4 {
+		return ErrInvalidLength
+	}
+	count := binary.BigEndian.Uint32(data)
+	size := uint64(count) * PublicKeySize
+	if uint64(len(data)-4) < size+1 {
+		return ErrInvalidLength
+	}
+	tag := data[4+size:]
+	if int(tag[0]) != len(tag)-1 {
+		return ErrInvalidLength
+	}
+	pubs := make([]PublicKey, count)
+	for i := range pubs {
+		var err error
+		offset := 4 + i*PublicKeySize
+		if pubs[i], err = ParsePublicKey(data[offset : offset+PublicKeySize]); err != nil {
+			return err
+		}
+	}
+	return group.set(pubs, string(tag[1:]))
+}
+
+// set sets the group of the public keys hashing by the suite with the tag
+func (group *Group) set(pubs []PublicKey, tag string) error {
+	res, err := NewGroup(pubs)
+	if err != nil {
+		return err
+	}
+	res.suite = multisigSuiteOf(tag)
+	*group = *res
+	return nil
+}
+
+type groupJSON struct {
+	ID         string      `json:"id"`
+	PublicKeys []PublicKey `json:"publicKeys"`
+	Suite      string      `json:"suite,omitempty"`
+}
+
+// MarshalJSON encodes the group as its ID, the public keys of the members
+// and the tag of its suite, omitted for LegacyMultisigSuite
+func (group Group) MarshalJSON() ([]byte, error) {
+	return json.Marshal(groupJSON{ID: hex.EncodeToString(group.id[:]), PublicKeys: group.pubs, Suite: group.suite.tag})
+}
+
+// UnmarshalJSON decodes the group encoded by MarshalJSON. The ID is
+// optional, but must match the members if present.
+func (group *Group) UnmarshalJSON(data []byte) error {
+	var raw groupJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	var res Group
+	if err := res.set(raw.PublicKeys, raw.Suite); err != nil {
+		return err
+	}
+	if raw.ID != "" && raw.ID != hex.EncodeToString(res.id[:]) {
+		return ErrGroupID
+	}
+	*group = res
+	return nil
+}
@@ -0,0 +1,191 @@
+package bls
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+// Hashing to G1 as specified by RFC 9380 for the suite
+// BN254G1_XMD:SHA-256_SVDW_RO_: expand_message_xmd with SHA-256,
+// Shallue-van de Woestijne map with Z = 1 and no cofactor clearing (h = 1).
+
+const (
+	h2cFieldElementSize = 48 // L = ceil((ceil(log2(p)) + k) / 8), k = 128
+	h2cMaxDSTSize       = 255
+)
+
+var (
+	curveB = big.NewInt(3)
+	svdwZ  = big.NewInt(1)
+
+	svdwC1       = big.NewInt(4)                               // g(Z)
+	svdwC2       = fpDiv(fpNeg(svdwZ), big.NewInt(2))          // -Z / 2
+	svdwC3       = fpSqrtEven(fpNeg(big.NewInt(12)))           // sqrt(-g(Z) * 3 * Z^2), sgn0(c3) = 0
+	svdwC4       = fpDiv(fpNeg(big.NewInt(16)), big.NewInt(3)) // -4 * g(Z) / (3 * Z^2)
+	pMinus1Over2 = new(big.Int).Rsh(new(big.Int).Sub(bn256.P, big.NewInt(1)), 1)
+	pPlus1Over4  = new(big.Int).Rsh(new(big.Int).Add(bn256.P, big.NewInt(1)), 2)
+)
+
+// hashToCurve hashes the message to a point of G1 under the domain
+// separation tag
+func hashToCurve(message, dst []byte) *bn256.G1 {
+	u := hashToField(message, dst, 2)
+	q := mapToCurveSVDW(u[0])
+	return q.Add(q, mapToCurveSVDW(u[1]))
+}
+
+// hashToField hashes the message to count elements of Fp
+func hashToField(message, dst []byte, count int) []*big.Int {
+	uniform, err := expandMessageXMD(message, dst, count*h2cFieldElementSize)
+	if err != nil {
+		panic(err)
+	}
+	res := make([]*big.Int, count)
+	for i := range res {
+		chunk := uniform[i*h2cFieldElementSize : (i+1)*h2cFieldElementSize]
+		res[i] = new(big.Int).SetBytes(chunk)
+		res[i].Mod(res[i], bn256.P)
+	}
+	return res
+}
+
+// expandMessageXMD implements expand_message_xmd with SHA-256
+func expandMessageXMD(message, dst []byte, size int) ([]byte, error) {
+	if len(dst) > h2cMaxDSTSize {
+		hash := sha256.Sum256(append([]byte("H2C-OVERSIZE-DST-"), dst...))
+		dst = hash[:]
+	}
+	ell := (size + sha256.Size - 1) / sha256.Size
+	if ell > 255 || size > 65535 {
+		return nil, errors.New("bls: requested too many bytes from expand_message_xmd")
+	}
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+
+	hasher := sha256.New()
+	hasher.Write(make([]byte, sha256.BlockSize)) // Z_pad
+	hasher.Write(message)
+	hasher.Write([]byte{byte(size >> 8), byte(size), 0})
+	hasher.Write(dstPrime)
+	b0 := hasher.Sum(nil)
+
+	res := make([]byte, 0, ell*sha256.Size)
+	bi := make([]byte, sha256.Size)
+	for i := 1; i <= ell; i++ {
+		for j := range bi {
+			bi[j] ^= b0[j] // b0 xor b(i-1), b0 for i = 1
+		}
+		hasher.Reset()
+		hasher.Write(bi)
+		hasher.Write([]byte{byte(i)})
+		hasher.Write(dstPrime)
+		bi = hasher.Sum(nil)
+		res = append(res, bi...)
+	}
+	return res[:size], nil
+}
+
+// mapToCurveSVDW maps the field element to a point of G1 using the
+// Shallue-van de Woestijne method (RFC 9380, section 6.6.1)
+func mapToCurveSVDW(u *big.Int) *bn256.G1 {
+	tv1 := fpMul(fpMul(u, u), svdwC1)
+	tv2 := fpAdd(big.NewInt(1), tv1)
+	tv1 = fpSub(big.NewInt(1), tv1)
+	tv3 := fpInv0(fpMul(tv1, tv2))
+	tv4 := fpMul(fpMul(fpMul(u, tv1), tv3), svdwC3)
+
+	x1 := fpSub(svdwC2, tv4)
+	x2 := fpAdd(svdwC2, tv4)
+	x3 := fpMul(tv2, tv2)
+	x3 = fpMul(x3, tv3)
+	x3 = fpMul(x3, x3)
+	x3 = fpAdd(fpMul(x3, svdwC4), svdwZ)
+
+	var x *big.Int
+	switch {
+	case fpIsSquare(curveG(x1)):
+		x = x1
+	case fpIsSquare(curveG(x2)):
+		x = x2
+	default:
+		x = x3
+	}
+	y := fpSqrt(curveG(x))
+	if sgn0(u) != sgn0(y) {
+		y = fpNeg(y)
+	}
+	return g1FromAffine(x, y)
+}
+
+// curveG evaluates the right side of the curve equation x^3 + 3
+func curveG(x *big.Int) *big.Int {
+	return fpAdd(fpMul(fpMul(x, x), x), curveB)
+}
+
+// g1FromAffine converts the affine coordinates to a point of G1
+func g1FromAffine(x, y *big.Int) *bn256.G1 {
+	data := make([]byte, 64)
+	x.FillBytes(data[:32])
+	y.FillBytes(data[32:])
+	p := new(bn256.G1)
+	if _, err := p.Unmarshal(data); err != nil {
+		panic(err)
+	}
+	return p
+}
+
+func fpAdd(a, b *big.Int) *big.Int {
+	res := new(big.Int).Add(a, b)
+	return res.Mod(res, bn256.P)
+}
+
+func fpSub(a, b *big.Int) *big.Int {
+	res := new(big.Int).Sub(a, b)
+	return res.Mod(res, bn256.P)
+}
+
+func fpMul(a, b *big.Int) *big.Int {
+	res := new(big.Int).Mul(a, b)
+	return res.Mod(res, bn256.P)
+}
+
+func fpNeg(a *big.Int) *big.Int {
+	res := new(big.Int).Neg(a)
+	return res.Mod(res, bn256.P)
+}
+
+func fpDiv(a, b *big.Int) *big.Int {
+	return fpMul(a, fpInv0(b))
+}
+
+// fpInv0 returns the inverse of a, or 0 if a is 0
+func fpInv0(a *big.Int) *big.Int {
+	if a.Sign() == 0 {
+		return new(big.Int)
+	}
+	return new(big.Int).ModInverse(a, bn256.P)
+}
+
+func fpIsSquare(a *big.Int) bool {
+	return new(big.Int).Exp(a, pMinus1Over2, bn256.P).Cmp(big.NewInt(1)) <= 0
+}
+
+// fpSqrt returns a square root of a, which must be a square (p = 3 mod 4)
+func fpSqrt(a *big.Int) *big.Int {
+	return new(big.Int).Exp(a, pPlus1Over4, bn256.P)
+}
+
+// fpSqrtEven returns the square root of a with sgn0 = 0
+func fpSqrtEven(a *big.Int) *big.Int {
+	res := fpSqrt(a)
+	if sgn0(res) != 0 {
+		res = fpNeg(res)
+	}
+	return res
+}
+
+func sgn0(a *big.Int) uint {
+	return a.Bit(0)
+}
@@ -6,26 +6,64 @@ import (
 	"math/big"
 )
 
+// MarshalText encodes the private key as "REDACTED", so that it does not
+// leak through logs and API responses. Use ExportedPrivateKey to encode it
+// in plaintext.
+func (secretKey PrivateKey) MarshalText() ([]byte, error) {
+	return []byte(redacted), nil
+}
+
+// UnmarshalText reads the private key encoded by ExportedPrivateKey.MarshalText
+func (secretKey *PrivateKey) UnmarshalText(text []byte) error {
+	if string(text) == redacted {
+		*secretKey = PrivateKey{}
+		return ErrRedacted
+	}
+	priv, err := ReadPrivateKey(string(text))
+	*secretKey = priv
+	return err
+}
+
+// MarshalJSON encodes the private key as the string of MarshalText, null
+// for the empty key
 func (secretKey PrivateKey) MarshalJSON() ([]byte, error) {
 	if secretKey.p == nil {
 		return json.Marshal(nil)
 	}
-	return secretKey.p.MarshalJSON()
+	return json.Marshal(redacted)
 }
 
+// UnmarshalJSON reads the private key encoded by
+// ExportedPrivateKey.MarshalJSON or the decimal number of the former encoding
 func (secretKey *PrivateKey) UnmarshalJSON(data []byte) error {
-	priv, err := UnmarshalPrivateKey(data)
-	*secretKey = priv
-	return err
+	var str *string
+	if err := json.Unmarshal(data, &str); err != nil {
+		// the former encoding
+		priv, err := unmarshalDecimalPrivateKey(data)
+		*secretKey = priv
+		return err
+	}
+	if str == nil {
+		*secretKey = PrivateKey{}
+		return nil
+	}
+	return secretKey.UnmarshalText([]byte(*str))
 }
 
+// ReadPrivateKey reads the private key from up to 64 hex digits. Shorter
+// numbers lost their leading zeros and are padded back to 32 bytes.
 func ReadPrivateKey(str string) (PrivateKey, error) {
-	bin, err := hex.DecodeString(str)
+	if len(str) == 0 || len(str) > 2*PrivateKeySize {
+		return PrivateKey{}, ErrInvalidLength
+	}
+	if len(str)%2 == 1 {
+		str = "0" + str
+	}
+	raw, err := hex.DecodeString(str)
 	if err != nil {
 		return PrivateKey{}, err
 	}
-	p := new(big.Int).SetBytes(bin)
-	return PrivateKey{p: p}, err
+	return readPrivateKey(new(big.Int).SetBytes(raw))
 }
 
 func (publicKey PublicKey) MarshalJSON() ([]byte, error) {
@@ -45,17 +83,18 @@ func (publicKey *PublicKey) UnmarshalJSON(data []byte) error {
 	if err != nil {
 		return err
 	}
-	sig, err := UnmarshalPublicKey(raw)
-	*publicKey = sig
+	pub, err := unmarshalPublicKey(raw)
+	*publicKey = pub
 	return err
 }
 
+// ReadPublicKey reads the hex-encoded public key, compressed or not
 func ReadPublicKey(str string) (PublicKey, error) {
 	raw, err := hex.DecodeString(str)
 	if err != nil {
 		return PublicKey{}, err
 	}
-	return UnmarshalPublicKey(raw)
+	return unmarshalPublicKey(raw)
 }
 
 func (signature Signature) MarshalJSON() ([]byte, error) {
@@ -75,19 +114,23 @@ func (signature *Signature) UnmarshalJSON(data []byte) error {
 	if err != nil {
 		return err
 	}
-	sig, err := UnmarshalSignature(raw)
+	sig, err := unmarshalSignature(raw)
 	*signature = sig
 	return err
 }
 
+// ReadSignature reads the hex-encoded signature, compressed or not
 func ReadSignature(str string) (Signature, error) {
 	raw, err := hex.DecodeString(str)
 	if err != nil {
 		return Signature{}, err
 	}
-	return UnmarshalSignature(raw)
+	return unmarshalSignature(raw)
 }
 
+// MarshalBitmask encodes the bitmask as big-endian bytes without leading
+// zeros, nil for a nil bitmask. Multisig.MarshalBinary encodes the whole
+// multisignature.
 func MarshalBitmask(mask *big.Int) []byte {
 	if mask == nil {
 		return nil
@@ -95,6 +138,7 @@ func MarshalBitmask(mask *big.Int) []byte {
 	return mask.Bytes()
 }
 
+// UnmarshalBitmask decodes the bitmask encoded by MarshalBitmask
 func UnmarshalBitmask(data []byte) *big.Int {
 	if data == nil {
 		return nil
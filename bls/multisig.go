@@ -3,6 +3,7 @@ package bls
 import (
 	"crypto/sha256"
 	"math/big"
+	"math/bits"
 
 	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
 )
@@ -28,17 +29,47 @@ func NewZeroMultisig() Multisig {
 // * the aggregated public key of all its signers (whether signed or not),
 // * the aggregated public key of participated signers (who really signed),
 // * and the bitmask of signers
+//
+// The message and the indices are hashed by LegacyMultisigSuite as in
+// Solidity. The
+// indices are uint32, so a group has at most 2^32 members and bitmasks
+// above 2^32 bits are rejected.
 func (multi Multisig) Verify(aggPublicKey PublicKey, message []byte) bool {
+	return LegacyMultisigSuite.Verify(multi, aggPublicKey, message)
+}
+
+// VerifyLegacy checks the BLS multisignature of groups set up by the former
+// single-byte indices, which wrapped modulo 256 for groups above 256
+// members. Groups of up to 256 members verify the same by Verify.
+//
+// Deprecated: Set up the group again and use Verify.
+func (multi Multisig) VerifyLegacy(aggPublicKey PublicKey, message []byte) bool {
+	return multi.verify(LegacyMultisigSuite, aggPublicKey, message, func(index int) uint32 {
+		return uint32(byte(index))
+	})
+}
+
+func (multi Multisig) verify(suite MultisigSuite, aggPublicKey PublicKey, message []byte, encodeIndex func(int) uint32) bool {
+	if aggPublicKey.p == nil || multi.PartPublicKey.p == nil || multi.PartSignature.p == nil {
+		return false
+	}
+	if multi.PartMask == nil || multi.PartMask.Sign() < 0 {
+		return false
+	}
+	if int64(multi.PartMask.BitLen()) > maxGroupIndices {
+		// the indices would wrap modulo 2^32
+		return false
+	}
 	sum := new(bn256.G1).Set(&zeroG1)
 	mask := new(big.Int).Set(multi.PartMask)
 	for index := 0; mask.Sign() != 0; index++ {
 		if multi.PartMask.Bit(index) != 0 {
 			mask.SetBit(mask, index, 0)
-			sum.Add(sum, hashToPointIndex(aggPublicKey.p, byte(index)))
+			sum.Add(sum, suite.hashToPointIndex(aggPublicKey.p, encodeIndex(index)))
 		}
 	}
 
-	a := []*bn256.G1{new(bn256.G1).Neg(multi.PartSignature.p), hashToPointMsg(aggPublicKey.p, message), sum}
+	a := []*bn256.G1{new(bn256.G1).Neg(multi.PartSignature.p), suite.hashToPointMsg(aggPublicKey.p, message), sum}
 	b := []*bn256.G2{&g2, multi.PartPublicKey.p, aggPublicKey.p}
 	return bn256.PairingCheck(a, b)
 }
@@ -82,3 +113,24 @@ func AggregatePublicKeys(pubs []PublicKey, anticoefs []big.Int) PublicKey {
 	}
 	return PublicKey{p: &res}
 }
+
+// MultisigMaskWords splits the bitmask into 256-bit words, the lowest word
+// first, as taken by verifyMultisigWide in BlsSignatureVerification.sol
+func MultisigMaskWords(mask *big.Int) []*big.Int {
+	words := make([]*big.Int, (mask.BitLen()+255)/256)
+	wordMask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	for i := range words {
+		words[i] = new(big.Int).Rsh(mask, uint(i*256))
+		words[i].And(words[i], wordMask)
+	}
+	return words
+}
+
+// popCount returns the number of bits set in the non-negative mask
+func popCount(mask *big.Int) int {
+	count := 0
+	for _, word := range mask.Bits() {
+		count += bits.OnesCount(uint(word))
+	}
+	return count
+}
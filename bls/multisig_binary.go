@@ -0,0 +1,75 @@
+package bls
+
+import (
+	"encoding/binary"
+	"math/big"
+)
+
+// MultisigVersion is the version of the binary encoding of Multisig
+const MultisigVersion = 1
+
+// multisigHeaderSize is the size of the encoding with an empty bitmask:
+// version, compressed signature and public key, bitmask length
+const multisigHeaderSize = 1 + CompressedSignatureSize + CompressedPublicKeySize + 4
+
+// MarshalBinary encodes the multisignature as
+//
+//	version (1 byte) || signature (32 bytes, compressed) ||
+//	public key (64 bytes, compressed) || bitmask length (4 bytes) || bitmask
+//
+// where the bitmask is big-endian without leading zeros, so that every
+// multisignature has the only encoding.
+func (multi Multisig) MarshalBinary() ([]byte, error) {
+	if err := multi.PartSignature.validate(); err != nil {
+		return nil, err
+	}
+	if multi.PartPublicKey.p == nil {
+		return nil, ErrNilKey
+	}
+	if multi.PartMask == nil || multi.PartMask.Sign() < 0 {
+		return nil, ErrInvalidMask
+	}
+	mask := multi.PartMask.Bytes()
+	res := make([]byte, multisigHeaderSize, multisigHeaderSize+len(mask))
+	res[0] = MultisigVersion
+	copy(res[1:], multi.PartSignature.MarshalCompressed())
+	copy(res[1+CompressedSignatureSize:], multi.PartPublicKey.MarshalCompressed())
+	binary.BigEndian.PutUint32(res[multisigHeaderSize-4:], uint32(len(mask)))
+	return append(res, mask...), nil
+}
+
+// UnmarshalBinary decodes the multisignature encoded by MarshalBinary,
+// rejecting unknown versions, invalid points and non-canonical bitmasks
+func (multi *Multisig) UnmarshalBinary(data []byte) error {
+	if len(data) < multisigHeaderSize {
+		return ErrInvalidLength
+	}
+	if data[0] != MultisigVersion {
+		return ErrUnsupportedVersion
+	}
+	data = data[1:]
+	sig, err := UnmarshalCompressedSignature(data[:CompressedSignatureSize])
+	if err != nil {
+		return err
+	}
+	data = data[CompressedSignatureSize:]
+	pub, err := UnmarshalCompressedPublicKey(data[:CompressedPublicKeySize])
+	if err != nil {
+		return err
+	}
+	data = data[CompressedPublicKeySize:]
+	size := binary.BigEndian.Uint32(data)
+	data = data[4:]
+	if uint64(len(data)) != uint64(size) {
+		return ErrInvalidLength
+	}
+	if size > 0 && data[0] == 0 {
+		return ErrNonCanonical
+	}
+	*multi = Multisig{
+		PartSignature: sig,
+		PartPublicKey: pub,
+		PartMask:      new(big.Int).SetBytes(data),
+	}
+	return nil
+}
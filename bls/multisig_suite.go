@@ -0,0 +1,156 @@
+package bls
+
+import (
+	"encoding/binary"
+	"math/big"
+
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+// MultisigSuite defines how multisignatures hash the message H(P, m) and the
+// membership indices H(P, i) to G1. The suites of NewMultisigSuite hash them
+// in separate domains, so that a membership key part is not the partial
+// signature of a message, and neither is a signature of SignAugmented.
+type MultisigSuite struct {
+	tag     string // empty for the legacy suite
+	message Ciphersuite
+	index   Ciphersuite
+}
+
+var (
+	// LegacyMultisigSuite hashes by try-and-increment without domain
+	// separation, as verifyMultisig in BlsSignatureVerification.sol does. It
+	// is used by Multisign, Multisig.Verify and the other multisignature
+	// functions.
+	LegacyMultisigSuite = MultisigSuite{message: LegacyCiphersuite, index: LegacyCiphersuite}
+	// SeparatedMultisigSuite hashes by RFC 9380 with separate domain
+	// separation tags of messages and indices. It is opt-in, since the
+	// Solidity verifier and the membership keys set up before do not use it.
+	SeparatedMultisigSuite = NewMultisigSuite("BLS_MULTISIG_BN254G1_XMD:SHA-256_SVDW_RO_")
+)
+
+// NewMultisigSuite returns the suite hashing messages by the domain
+// separation tag with "MSG_" appended and indices with "IDX_" appended
+func NewMultisigSuite(tag string) MultisigSuite {
+	if tag == "" {
+		panic("bls: empty domain separation tag")
+	}
+	return MultisigSuite{
+		tag:     tag,
+		message: NewCiphersuite(tag + "MSG_"),
+		index:   NewCiphersuite(tag + "IDX_"),
+	}
+}
+
+// multisigSuiteOf returns the suite with the tag, LegacyMultisigSuite for
+// the empty one
+func multisigSuiteOf(tag string) MultisigSuite {
+	if tag == "" {
+		return LegacyMultisigSuite
+	}
+	return NewMultisigSuite(tag)
+}
+
+// Tag returns the domain separation tag of NewMultisigSuite identifying the
+// suite, empty for LegacyMultisigSuite
+func (suite MultisigSuite) Tag() string {
+	return suite.tag
+}
+
+// hashToPointMsg performs "message augmentation": hashes the message and the
+// point to the point of G1 curve (a signature)
+func (suite MultisigSuite) hashToPointMsg(p *bn256.G2, message []byte) *bn256.G1 {
+	return suite.message.hashToPoint(augment(p, message))
+}
+
+// maxGroupIndices is the number of distinct uint32 member indices
+const maxGroupIndices = 1 << 32
+
+// hashToPointIndex hashes the aggregated public key (G2 point) and the given
+// index (of the signer within a group of signers) to the point in G1 curve (a
+// signature). The index is encoded as a 32-byte big-endian integer like
+// uint256 in Solidity, so indices below 256 hash the same as the former
+// single-byte ones. Only the indices below 2^32 are supported, which is the
+// limit of the group size.
+func (suite MultisigSuite) hashToPointIndex(pub *bn256.G2, index uint32) *bn256.G1 {
+	data := make([]byte, 32)
+	binary.BigEndian.PutUint32(data[28:], index)
+	return suite.index.hashToPoint(augment(pub, data))
+}
+
+// HashToPointIndex hashes the aggregated public key and the index to G1. The
+// index is uint32: groups are limited to 2^32 members.
+func (suite MultisigSuite) HashToPointIndex(pub PublicKey, index uint32) Signature {
+	return Signature{p: suite.hashToPointIndex(pub.p, index)}
+}
+
+// Multisign generates BLS multi-signature of the given message, aggregated
+// public key of all participants and the membership key of the signer
+func (suite MultisigSuite) Multisign(secretKey PrivateKey, message []byte, aggPublicKey PublicKey, membershipKey Signature) Signature {
+	s := new(bn256.G1).ScalarMult(suite.hashToPointMsg(aggPublicKey.p, message), secretKey.p)
+	s.Add(s, membershipKey.p)
+	return Signature{p: s}
+}
+
+// GenerateMembershipKeyPart generates the participant signature to be
+// aggregated into membership key of the member with the index below 2^32
+func (suite MultisigSuite) GenerateMembershipKeyPart(secretKey PrivateKey, index uint32, aggPub PublicKey, anticoef big.Int) Signature {
+	res := new(bn256.G1).ScalarMult(suite.hashToPointIndex(aggPub.p, index), secretKey.p)
+	res.ScalarMult(res, &anticoef)
+	return Signature{p: res}
+}
+
+// VerifyMembershipKeyPart verifies membership key part i ((a⋅pk)×H(P, i))
+// against aggregated public key (P) and public key of the party (pk×G)
+func (suite MultisigSuite) VerifyMembershipKeyPart(part Signature, aggPublicKey PublicKey, partPublicKey PublicKey, anticoef big.Int, index uint32) bool {
+	if part.p == nil || aggPublicKey.p == nil || partPublicKey.p == nil {
+		return false
+	}
+	hashPoint := suite.hashToPointIndex(aggPublicKey.p, index)
+	pub := new(bn256.G2).ScalarMult(partPublicKey.p, &anticoef)
+
+	a := []*bn256.G1{new(bn256.G1).Neg(part.p), hashPoint}
+	b := []*bn256.G2{&g2, pub}
+	return bn256.PairingCheck(a, b)
+}
+
+// VerifyMultisignPart checks the partial signature generated by Multisign of
+// the group member with the given index and public key:
+//
+// e(S, G) = e(H(P, m), pk)⋅e(H(P, i), P)
+func (suite MultisigSuite) VerifyMultisignPart(signature Signature, aggPublicKey PublicKey, publicKey PublicKey, index uint32, message []byte) bool {
+	if signature.p == nil || aggPublicKey.p == nil || publicKey.p == nil {
+		return false
+	}
+	a := []*bn256.G1{new(bn256.G1).Neg(signature.p), suite.hashToPointMsg(aggPublicKey.p, message), suite.hashToPointIndex(aggPublicKey.p, index)}
+	b := []*bn256.G2{&g2, publicKey.p, aggPublicKey.p}
+	return bn256.PairingCheck(a, b)
+}
+
+// Verify checks the BLS multisignature of the message, see Multisig.Verify
+func (suite MultisigSuite) Verify(multi Multisig, aggPublicKey PublicKey, message []byte) bool {
+	return multi.verify(suite, aggPublicKey, message, func(index int) uint32 {
+		return uint32(index)
+	})
+}
+
+// VerifyChecked checks the BLS multisignature of the message, see
+// Multisig.VerifyChecked
+func (suite MultisigSuite) VerifyChecked(multi Multisig, aggPublicKey PublicKey, message []byte) error {
+	if err := aggPublicKey.validate(); err != nil {
+		return err
+	}
+	if multi.PartPublicKey.p == nil {
+		return ErrNilKey
+	}
+	if err := multi.PartSignature.validate(); err != nil {
+		return err
+	}
+	if multi.PartMask == nil || multi.PartMask.Sign() <= 0 {
+		return ErrInvalidMask
+	}
+	if !suite.Verify(multi, aggPublicKey, message) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
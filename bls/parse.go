@@ -0,0 +1,88 @@
+package bls
+
+import (
+	"math/big"
+
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+const (
+	PrivateKeySize = 32  // big-endian scalar
+	SignatureSize  = 64  // G1 point: x, y
+	PublicKeySize  = 128 // G2 point: x.im, x.re, y.im, y.re
+)
+
+// ParsePublicKey strictly reads the public key: the encoding must be
+// canonical and the point must be a non-identity element of G2
+func ParsePublicKey(raw []byte) (PublicKey, error) {
+	if len(raw) != PublicKeySize {
+		return PublicKey{}, ErrInvalidLength
+	}
+	coords, err := readCoordinates(raw)
+	if err != nil {
+		return PublicKey{}, err
+	}
+	x := fp2{re: coords[1], im: coords[0]}
+	y := fp2{re: coords[3], im: coords[2]}
+	if !isOnTwist(x, y) {
+		return PublicKey{}, ErrNotOnCurve
+	}
+	p := new(bn256.G2)
+	if _, err := p.Unmarshal(raw); err != nil {
+		// The point is on the curve, bn256 rejects it as out of G2
+		return PublicKey{}, ErrNotInSubgroup
+	}
+	return PublicKey{p: p}, nil
+}
+
+// ParseSignature strictly reads the signature: the encoding must be
+// canonical and the point must be a non-identity element of G1
+func ParseSignature(raw []byte) (Signature, error) {
+	if len(raw) != SignatureSize {
+		return Signature{}, ErrInvalidLength
+	}
+	coords, err := readCoordinates(raw)
+	if err != nil {
+		return Signature{}, err
+	}
+	// The cofactor of G1 is 1, so every point of the curve is in G1
+	if fpMul(coords[1], coords[1]).Cmp(curveG(coords[0])) != 0 {
+		return Signature{}, ErrNotOnCurve
+	}
+	p := new(bn256.G1)
+	if _, err := p.Unmarshal(raw); err != nil {
+		return Signature{}, ErrNotOnCurve
+	}
+	return Signature{p: p}, nil
+}
+
+// ParsePrivateKey strictly reads the private key from its 32-byte
+// big-endian encoding: 0 < key < group order
+func ParsePrivateKey(raw []byte) (PrivateKey, error) {
+	if len(raw) != PrivateKeySize {
+		return PrivateKey{}, ErrInvalidLength
+	}
+	p := new(big.Int).SetBytes(raw)
+	if p.Sign() == 0 || p.Cmp(bn256.Order) >= 0 {
+		return PrivateKey{}, ErrScalarRange
+	}
+	return PrivateKey{p: p}, nil
+}
+
+// readCoordinates reads the 32-byte field elements of the point, rejecting
+// the non-canonical ones and the point at infinity (all zeros)
+func readCoordinates(raw []byte) ([]*big.Int, error) {
+	coords := make([]*big.Int, len(raw)/32)
+	infinity := true
+	for i := range coords {
+		var ok bool
+		if coords[i], ok = readFp(raw[i*32 : (i+1)*32]); !ok {
+			return nil, ErrNonCanonical
+		}
+		infinity = infinity && coords[i].Sign() == 0
+	}
+	if infinity {
+		return nil, ErrInfinity
+	}
+	return coords, nil
+}
@@ -0,0 +1,93 @@
+package bls
+
+import (
+	"math/big"
+)
+
+// Policy defines the subsets of the group whose multisignatures are
+// accepted. The rules with zero values are not checked. MinFraction fails
+// without GroupSize, since the fraction of an unknown size can not be met.
+type Policy struct {
+	GroupSize   int      // number of members in the group, signers must be below it
+	MinSigners  int      // minimum number of signers
+	MinFraction *big.Rat // minimum fraction of GroupSize that must sign, e.g. 2/3
+	Required    []uint32 // indices of the members who must sign
+}
+
+// PolicyRule identifies the rule of the policy
+type PolicyRule int
+
+const (
+	RuleNone       PolicyRule = iota // all the rules are satisfied
+	RuleGroupSize                    // a signer is out of the group
+	RuleMinSigners                   // too few signers
+	RuleFraction                     // too small fraction of the group signed
+	RuleRequired                     // a required member did not sign
+	RuleSignature                    // the multisignature is invalid
+)
+
+func (rule PolicyRule) String() string {
+	switch rule {
+	case RuleNone:
+		return "none"
+	case RuleGroupSize:
+		return "group size"
+	case RuleMinSigners:
+		return "minimum signers"
+	case RuleFraction:
+		return "minimum fraction"
+	case RuleRequired:
+		return "required members"
+	case RuleSignature:
+		return "signature"
+	}
+	return "unknown"
+}
+
+// PolicyResult is the outcome of VerifyWithPolicy
+type PolicyResult struct {
+	Failed  PolicyRule // the first rule failed, RuleNone if verified
+	Signers int        // number of signers in the mask
+	Missing []uint32   // required members who did not sign
+}
+
+// OK checks whether the multisignature satisfies the policy
+func (result PolicyResult) OK() bool {
+	return result.Failed == RuleNone
+}
+
+// VerifyWithPolicy checks that the signers satisfy the policy and then
+// verifies the multisignature by Verify
+func (multi Multisig) VerifyWithPolicy(aggPublicKey PublicKey, message []byte, policy Policy) PolicyResult {
+	if multi.PartMask == nil || multi.PartMask.Sign() < 0 {
+		return PolicyResult{Failed: RuleSignature}
+	}
+	result := PolicyResult{Signers: popCount(multi.PartMask)}
+	for _, index := range policy.Required {
+		if multi.PartMask.Bit(int(index)) == 0 {
+			result.Missing = append(result.Missing, index)
+		}
+	}
+
+	switch {
+	case policy.GroupSize > 0 && multi.PartMask.BitLen() > policy.GroupSize:
+		result.Failed = RuleGroupSize
+	case result.Signers < policy.MinSigners:
+		result.Failed = RuleMinSigners
+	case policy.MinFraction != nil && !fractionMet(result.Signers, policy.GroupSize, policy.MinFraction):
+		result.Failed = RuleFraction
+	case len(result.Missing) != 0:
+		result.Failed = RuleRequired
+	case !multi.Verify(aggPublicKey, message):
+		result.Failed = RuleSignature
+	}
+	return result
+}
+
+// fractionMet checks signers/size >= fraction, false for the unknown size
+func fractionMet(signers, size int, fraction *big.Rat) bool {
+	if size <= 0 {
+		return false
+	}
+	return big.NewRat(int64(signers), int64(size)).Cmp(fraction) >= 0
+}
@@ -0,0 +1,50 @@
+package bls
+
+import (
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+// possessionDomain separates proofs of possession from signatures of
+// ordinary messages. It must match POSSESSION_DOMAIN in BlsSignatureVerification.sol.
+var possessionDomain = []byte("BLS_POP_BN256G1_")
+
+// possessionMessage returns the message signed by the proof of possession:
+// domain || public key
+func possessionMessage(pub *bn256.G2) []byte {
+	var data []byte
+	data = append(data, possessionDomain...)
+	data = append(data, pub.Marshal()...)
+	return data
+}
+
+// PossessionMessage returns the message signed by the proof of possession of
+// the public key, as hashed by the on-chain verifier
+func (pub PublicKey) PossessionMessage() []byte {
+	return possessionMessage(pub.p)
+}
+
+// ProvePossession generates the proof of possession of the private key: a
+// signature of the corresponding public key under a separate domain
+func (secretKey PrivateKey) ProvePossession() Signature {
+	pub := new(bn256.G2).ScalarBaseMult(secretKey.p)
+	return LegacyCiphersuite.Sign(secretKey, possessionMessage(pub))
+}
+
+// VerifyPossession checks the proof of possession of the private key
+// corresponding to the public key
+func (pub PublicKey) VerifyPossession(proof Signature) bool {
+	if pub.p == nil {
+		return false
+	}
+	return proof.Verify(pub, possessionMessage(pub.p))
+}
+
+// FastAggregateVerify checks the aggregated signature of the same message
+// by all the given public keys.
+//
+// It is only safe if the proof of possession of every public key has been
+// verified, otherwise it is open to rogue key attacks. Use Multisig or
+// anti-rogue coefficients for keys without proofs of possession.
+func FastAggregateVerify(pubs []PublicKey, message []byte, sig Signature) bool {
+	return LegacyCiphersuite.FastAggregateVerify(pubs, message, sig)
+}
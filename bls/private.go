@@ -3,9 +3,9 @@ package bls
 import (
 	"crypto"
 	"math/big"
+	"strings"
 
 	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
-	"github.com/keep-network/keep-core/pkg/altbn128"
 )
 
 type PrivateKey struct {
@@ -23,36 +23,70 @@ func (priv PrivateKey) PublicKey() PublicKey {
 
 // Sign generates a simple BLS signature of the given message
 func (secretKey PrivateKey) Sign(message []byte) Signature {
-	hashPoint := altbn128.G1HashToPoint(message)
-	return Signature{p: new(bn256.G1).ScalarMult(hashPoint, secretKey.p)}
+	return LegacyCiphersuite.Sign(secretKey, message)
+}
+
+// SignWith generates a simple BLS signature of the given message hashed by
+// the ciphersuite
+func (secretKey PrivateKey) SignWith(cs Ciphersuite, message []byte) Signature {
+	return cs.Sign(secretKey, message)
 }
 
 // Multisign generates BLS multi-signature of the given message, aggregated
-// public key of all participants and the membership key of the signer
+// public key of all participants and the membership key of the signer by
+// LegacyMultisigSuite
 func (secretKey PrivateKey) Multisign(message []byte, aggPublicKey PublicKey, membershipKey Signature) Signature {
-	s := new(bn256.G1).ScalarMult(hashToPointMsg(aggPublicKey.p, message), secretKey.p)
-	s.Add(s, membershipKey.p)
-	return Signature{p: s}
+	return LegacyMultisigSuite.Multisign(secretKey, message, aggPublicKey, membershipKey)
 }
 
-// GenerateMembershipKeyPart generates the participant signature to be aggregated into membership key
-func (secretKey PrivateKey) GenerateMembershipKeyPart(index byte, aggPub PublicKey, anticoef big.Int) Signature {
-	res := new(bn256.G1).ScalarMult(hashToPointIndex(aggPub.p, index), secretKey.p)
-	res.ScalarMult(res, &anticoef)
-	return Signature{p: res}
+// GenerateMembershipKeyPart generates the participant signature to be
+// aggregated into membership key by LegacyMultisigSuite
+func (secretKey PrivateKey) GenerateMembershipKeyPart(index uint32, aggPub PublicKey, anticoef big.Int) Signature {
+	return LegacyMultisigSuite.GenerateMembershipKeyPart(secretKey, index, aggPub, anticoef)
 }
 
+// Marshal encodes the private key as a 32-byte big-endian scalar modulo
+// the group order, nil for the empty key
 func (secretKey PrivateKey) Marshal() []byte {
 	if secretKey.p == nil {
 		return nil
 	}
-	res, _ := secretKey.p.MarshalJSON()
-	return res
+	res := make([]byte, PrivateKeySize)
+	return new(big.Int).Mod(secretKey.p, bn256.Order).FillBytes(res)
 }
 
-// UnmarshalBlsPrivateKey reads the private key from the given byte array
+// UnmarshalPrivateKey reads the private key encoded by Marshal or, for the
+// migration from the former encoding, the decimal number. The data of ASCII
+// digits only is always read as the decimal number, also of 32 digits; the
+// 32-byte encoding of such a key is read by ParsePrivateKey. Unlike
+// ParsePrivateKey it accepts the zero key, which the checked API rejects.
 func UnmarshalPrivateKey(data []byte) (PrivateKey, error) {
-	p := new(big.Int)
-	err := p.UnmarshalJSON(data)
-	return PrivateKey{p: p}, err
+	if isDecimal(data) {
+		return unmarshalDecimalPrivateKey(data)
+	}
+	if len(data) != PrivateKeySize {
+		return PrivateKey{}, ErrInvalidLength
+	}
+	return readPrivateKey(new(big.Int).SetBytes(data))
+}
+
+// unmarshalDecimalPrivateKey reads the private key of the former encoding:
+// the decimal number
+func unmarshalDecimalPrivateKey(data []byte) (PrivateKey, error) {
+	if !isDecimal(data) {
+		return PrivateKey{}, ErrInvalidLength
+	}
+	p, _ := new(big.Int).SetString(string(data), 10)
+	return readPrivateKey(p)
+}
+
+func isDecimal(data []byte) bool {
+	return len(data) > 0 && strings.Trim(string(data), "0123456789") == ""
+}
+
+func readPrivateKey(p *big.Int) (PrivateKey, error) {
+	if p.Cmp(bn256.Order) >= 0 {
+		return PrivateKey{}, ErrScalarRange
+	}
+	return PrivateKey{p: p}, nil
 }
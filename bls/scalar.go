@@ -0,0 +1,42 @@
+package bls
+
+import (
+	"math/big"
+
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+)
+
+// Scalar arithmetic for protocols built on top of the keys, such as secret
+// sharing. All scalars are taken modulo the group order.
+
+// Order returns a copy of the order of G1 and G2 groups
+func Order() *big.Int {
+	return new(big.Int).Set(bn256.Order)
+}
+
+// PrivateKeyFromScalar returns the private key with the given scalar, which
+// must be in range 0 < scalar < Order
+func PrivateKeyFromScalar(scalar *big.Int) (PrivateKey, error) {
+	if scalar == nil || scalar.Sign() <= 0 || scalar.Cmp(bn256.Order) >= 0 {
+		return PrivateKey{}, ErrScalarRange
+	}
+	return PrivateKey{p: new(big.Int).Set(scalar)}, nil
+}
+
+// Scalar returns a copy of the scalar of the private key
+func (secretKey PrivateKey) Scalar() *big.Int {
+	if secretKey.p == nil {
+		return nil
+	}
+	return new(big.Int).Set(secretKey.p)
+}
+
+// ScalarMult multiplies the public key by the scalar
+func (pub PublicKey) ScalarMult(k *big.Int) PublicKey {
+	return PublicKey{p: new(bn256.G2).ScalarMult(pub.p, k)}
+}
+
+// ScalarMult multiplies the signature by the scalar
+func (signature Signature) ScalarMult(k *big.Int) Signature {
+	return Signature{p: new(bn256.G1).ScalarMult(signature.p, k)}
+}
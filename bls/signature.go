@@ -4,7 +4,6 @@ import (
 	"math/big"
 
 	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
-	"github.com/keep-network/keep-core/pkg/altbn128"
 )
 
 type Signature struct {
@@ -16,24 +15,24 @@ func ZeroSignature() Signature {
 	return Signature{p: new(bn256.G1).Set(&zeroG1)}
 }
 
-// Verify checks the BLS signature of the message against the public key of its signer
+// Verify checks the BLS signature of the message against the public key of
+// its signer. Like VerifyChecked it rejects the public key at infinity, but
+// returns false instead of the error.
 func (signature Signature) Verify(publicKey PublicKey, message []byte) bool {
-	hashPoint := altbn128.G1HashToPoint(message)
+	return LegacyCiphersuite.Verify(publicKey, message, signature)
+}
 
-	a := []*bn256.G1{new(bn256.G1).Neg(signature.p), hashPoint}
-	b := []*bn256.G2{&g2, publicKey.p}
-	return bn256.PairingCheck(a, b)
+// VerifyWith checks the BLS signature of the message hashed by the
+// ciphersuite against the public key of its signer
+func (signature Signature) VerifyWith(cs Ciphersuite, publicKey PublicKey, message []byte) bool {
+	return cs.Verify(publicKey, message, signature)
 }
 
 // VerifyMembershipKeyPart verifies membership key part i ((a⋅pk)×H(P, i))
-// against aggregated public key (P) and public key of the party (pk×G)
-func (signature Signature) VerifyMembershipKeyPart(aggPublicKey PublicKey, partPublicKey PublicKey, anticoef big.Int, index byte) bool {
-	hashPoint := hashToPointIndex(aggPublicKey.p, index)
-	pub := new(bn256.G2).ScalarMult(partPublicKey.p, &anticoef)
-
-	a := []*bn256.G1{new(bn256.G1).Neg(signature.p), hashPoint}
-	b := []*bn256.G2{&g2, pub}
-	return bn256.PairingCheck(a, b)
+// against aggregated public key (P) and public key of the party (pk×G) by
+// LegacyMultisigSuite
+func (signature Signature) VerifyMembershipKeyPart(aggPublicKey PublicKey, partPublicKey PublicKey, anticoef big.Int, index uint32) bool {
+	return LegacyMultisigSuite.VerifyMembershipKeyPart(signature, aggPublicKey, partPublicKey, anticoef, index)
 }
 
 // Aggregate adds the given signatures
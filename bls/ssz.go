@@ -0,0 +1,393 @@
+package bls
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"math/big"
+)
+
+// SSZ encoding of the consensus specifications. The points are compressed,
+// so a public key is Bytes64 and a signature is Bytes32:
+//
+//	class Multisig(Container):
+//	    signature: Bytes32
+//	    public_key: Bytes64
+//	    signers: Bitlist[MAX_GROUP_SIZE]
+//
+//	class Group(Container):
+//	    members: List[Bytes64, MAX_GROUP_SIZE]
+//	    suite: ByteList[255]
+//
+// The suite is the tag of the MultisigSuite of the group, empty for
+// LegacyMultisigSuite.
+
+// MaxGroupSize is the limit of the SSZ lists of group members and signers
+const MaxGroupSize = 2048
+
+const (
+	sszChunkSize       = 32
+	sszOffsetSize      = 4
+	multisigSSZFixed   = CompressedSignatureSize + CompressedPublicKeySize + sszOffsetSize
+	signersChunksLimit = (MaxGroupSize + 255) / 256
+	groupSSZFixed      = 2 * sszOffsetSize
+	suiteChunksLimit   = (maxSuiteTagSize + sszChunkSize - 1) / sszChunkSize
+)
+
+// zeroHashes[i] is the root of the Merkle tree of depth i with zero chunks
+var zeroHashes = func() [][32]byte {
+	res := make([][32]byte, 32)
+	for i := 1; i < len(res); i++ {
+		res[i] = hashPair(res[i-1], res[i-1])
+	}
+	return res
+}()
+
+// MarshalSSZ encodes the public key as SSZ Bytes64
+func (pub PublicKey) MarshalSSZ() ([]byte, error) {
+	if pub.p == nil {
+		return nil, ErrNilKey
+	}
+	return pub.MarshalCompressed(), nil
+}
+
+// UnmarshalSSZ decodes the public key encoded by MarshalSSZ
+func (pub *PublicKey) UnmarshalSSZ(data []byte) error {
+	res, err := UnmarshalCompressedPublicKey(data)
+	if err != nil {
+		return err
+	}
+	*pub = res
+	return nil
+}
+
+// SizeSSZ returns the size of the SSZ encoding
+func (pub PublicKey) SizeSSZ() int {
+	return CompressedPublicKeySize
+}
+
+// HashTreeRoot returns the SSZ hash tree root of the public key
+func (pub PublicKey) HashTreeRoot() ([32]byte, error) {
+	data, err := pub.MarshalSSZ()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return merkleize(packChunks(data), 2), nil
+}
+
+// MarshalSSZ encodes the signature as SSZ Bytes32
+func (signature Signature) MarshalSSZ() ([]byte, error) {
+	if err := signature.validate(); err != nil {
+		return nil, err
+	}
+	return signature.MarshalCompressed(), nil
+}
+
+// UnmarshalSSZ decodes the signature encoded by MarshalSSZ
+func (signature *Signature) UnmarshalSSZ(data []byte) error {
+	res, err := UnmarshalCompressedSignature(data)
+	if err != nil {
+		return err
+	}
+	*signature = res
+	return nil
+}
+
+// SizeSSZ returns the size of the SSZ encoding
+func (signature Signature) SizeSSZ() int {
+	return CompressedSignatureSize
+}
+
+// HashTreeRoot returns the SSZ hash tree root of the signature
+func (signature Signature) HashTreeRoot() ([32]byte, error) {
+	data, err := signature.MarshalSSZ()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	var res [32]byte
+	copy(res[:], data)
+	return res, nil
+}
+
+// MarshalSSZ encodes the multisignature as the SSZ container. The signers
+// bitlist has the length of the bitmask without trailing zeros.
+func (multi Multisig) MarshalSSZ() ([]byte, error) {
+	return multi.MarshalSSZWithSize(maskLength(multi.PartMask))
+}
+
+// MarshalSSZWithSize encodes the multisignature as the SSZ container with
+// the signers bitlist of the group size, as the aggregation bits of
+// consensus clients have the committee size
+func (multi Multisig) MarshalSSZWithSize(size int) ([]byte, error) {
+	sig, err := multi.PartSignature.MarshalSSZ()
+	if err != nil {
+		return nil, err
+	}
+	pub, err := multi.PartPublicKey.MarshalSSZ()
+	if err != nil {
+		return nil, err
+	}
+	signers, err := marshalBitlist(multi.PartMask, size)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]byte, 0, multisigSSZFixed+len(signers))
+	res = append(res, sig...)
+	res = append(res, pub...)
+	offset := make([]byte, sszOffsetSize)
+	binary.LittleEndian.PutUint32(offset, multisigSSZFixed)
+	res = append(res, offset...)
+	return append(res, signers...), nil
+}
+
+// UnmarshalSSZ decodes the multisignature encoded by MarshalSSZ. The
+// signers bitlist must not have trailing zero bits.
+func (multi *Multisig) UnmarshalSSZ(data []byte) error {
+	res, length, err := unmarshalMultisigSSZ(data)
+	if err != nil {
+		return err
+	}
+	if res.PartMask.BitLen() != length {
+		return ErrNonCanonical
+	}
+	*multi = res
+	return nil
+}
+
+// UnmarshalSSZWithSize decodes the multisignature encoded by
+// MarshalSSZWithSize, the signers bitlist must have the group size
+func (multi *Multisig) UnmarshalSSZWithSize(data []byte, size int) error {
+	res, length, err := unmarshalMultisigSSZ(data)
+	if err != nil {
+		return err
+	}
+	if length != size {
+		return ErrIndexRange
+	}
+	*multi = res
+	return nil
+}
+
+func unmarshalMultisigSSZ(data []byte) (Multisig, int, error) {
+	if len(data) < multisigSSZFixed {
+		return Multisig{}, 0, ErrInvalidLength
+	}
+	if binary.LittleEndian.Uint32(data[multisigSSZFixed-sszOffsetSize:]) != multisigSSZFixed {
+		return Multisig{}, 0, ErrNonCanonical
+	}
+	var res Multisig
+	if err := res.PartSignature.UnmarshalSSZ(data[:CompressedSignatureSize]); err != nil {
+		return Multisig{}, 0, err
+	}
+	if err := res.PartPublicKey.UnmarshalSSZ(data[CompressedSignatureSize : multisigSSZFixed-sszOffsetSize]); err != nil {
+		return Multisig{}, 0, err
+	}
+	mask, length, err := unmarshalBitlist(data[multisigSSZFixed:])
+	if err != nil {
+		return Multisig{}, 0, err
+	}
+	res.PartMask = mask
+	return res, length, nil
+}
+
+// SizeSSZ returns the size of the SSZ encoding
+func (multi Multisig) SizeSSZ() int {
+	return multisigSSZFixed + maskLength(multi.PartMask)/8 + 1
+}
+
+// HashTreeRoot returns the SSZ hash tree root of the multisignature
+func (multi Multisig) HashTreeRoot() ([32]byte, error) {
+	return multi.HashTreeRootWithSize(maskLength(multi.PartMask))
+}
+
+// HashTreeRootWithSize returns the SSZ hash tree root of the multisignature
+// with the signers bitlist of the group size, see MarshalSSZWithSize
+func (multi Multisig) HashTreeRootWithSize(size int) ([32]byte, error) {
+	sig, err := multi.PartSignature.HashTreeRoot()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	pub, err := multi.PartPublicKey.HashTreeRoot()
+	if err != nil {
+		return [32]byte{}, err
+	}
+	bits, err := bitlistBits(multi.PartMask, size)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	signers := mixInLength(merkleize(packChunks(bits), signersChunksLimit), size)
+	return merkleize([][32]byte{sig, pub, signers}, 3), nil
+}
+
+// MarshalSSZ encodes the public keys of the members and the tag of the
+// suite as the SSZ container
+func (group *Group) MarshalSSZ() ([]byte, error) {
+	if len(group.pubs) > MaxGroupSize || len(group.suite.tag) > maxSuiteTagSize {
+		return nil, ErrIndexRange
+	}
+	res := make([]byte, groupSSZFixed, group.SizeSSZ())
+	binary.LittleEndian.PutUint32(res, groupSSZFixed)
+	binary.LittleEndian.PutUint32(res[sszOffsetSize:], uint32(groupSSZFixed+len(group.pubs)*CompressedPublicKeySize))
+	for _, pub := range group.pubs {
+		res = append(res, pub.MarshalCompressed()...)
+	}
+	return append(res, group.suite.tag...), nil
+}
+
+// UnmarshalSSZ decodes the group encoded by MarshalSSZ
+func (group *Group) UnmarshalSSZ(data []byte) error {
+	if len(data) < groupSSZFixed {
+		return ErrInvalidLength
+	}
+	if binary.LittleEndian.Uint32(data) != groupSSZFixed {
+		return ErrNonCanonical
+	}
+	offset := binary.LittleEndian.Uint32(data[sszOffsetSize:])
+	if offset < groupSSZFixed || uint64(offset) > uint64(len(data)) {
+		return ErrNonCanonical
+	}
+	members, tag := data[groupSSZFixed:offset], data[offset:]
+	if len(members)%CompressedPublicKeySize != 0 {
+		return ErrInvalidLength
+	}
+	count := len(members) / CompressedPublicKeySize
+	if count > MaxGroupSize || len(tag) > maxSuiteTagSize {
+		return ErrIndexRange
+	}
+	pubs := make([]PublicKey, count)
+	for i := range pubs {
+		start := i * CompressedPublicKeySize
+		if err := pubs[i].UnmarshalSSZ(members[start : start+CompressedPublicKeySize]); err != nil {
+			return err
+		}
+	}
+	return group.set(pubs, string(tag))
+}
+
+// SizeSSZ returns the size of the SSZ encoding
+func (group *Group) SizeSSZ() int {
+	return groupSSZFixed + len(group.pubs)*CompressedPublicKeySize + len(group.suite.tag)
+}
+
+// HashTreeRoot returns the SSZ hash tree root of the public keys of the
+// members and the tag of the suite
+func (group *Group) HashTreeRoot() ([32]byte, error) {
+	if len(group.pubs) > MaxGroupSize || len(group.suite.tag) > maxSuiteTagSize {
+		return [32]byte{}, ErrIndexRange
+	}
+	roots := make([][32]byte, len(group.pubs))
+	for i, pub := range group.pubs {
+		var err error
+		if roots[i], err = pub.HashTreeRoot(); err != nil {
+			return [32]byte{}, err
+		}
+	}
+	members := mixInLength(merkleize(roots, MaxGroupSize), len(roots))
+	suite := mixInLength(merkleize(packChunks([]byte(group.suite.tag)), suiteChunksLimit), len(group.suite.tag))
+	return merkleize([][32]byte{members, suite}, 2), nil
+}
+
+// maskLength returns the bit length of the bitmask, 0 for nil
+func maskLength(mask *big.Int) int {
+	if mask == nil {
+		return 0
+	}
+	return mask.BitLen()
+}
+
+// bitlistBits returns the bits of the bitmask in little-endian order padded
+// with zeros to the length
+func bitlistBits(mask *big.Int, length int) ([]byte, error) {
+	if mask == nil || mask.Sign() < 0 {
+		return nil, ErrInvalidMask
+	}
+	if length < mask.BitLen() || length > MaxGroupSize {
+		return nil, ErrIndexRange
+	}
+	res := make([]byte, (length+7)/8)
+	for i := 0; i < length; i++ {
+		res[i/8] |= byte(mask.Bit(i)) << (i % 8)
+	}
+	return res, nil
+}
+
+// marshalBitlist encodes the bitmask as the SSZ bitlist of the length: the
+// bits in little-endian order followed by the delimiting bit
+func marshalBitlist(mask *big.Int, length int) ([]byte, error) {
+	bits, err := bitlistBits(mask, length)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]byte, length/8+1)
+	copy(res, bits)
+	res[length/8] |= 1 << (length % 8)
+	return res, nil
+}
+
+// unmarshalBitlist decodes the bitlist encoded by marshalBitlist and
+// returns its length
+func unmarshalBitlist(data []byte) (*big.Int, int, error) {
+	if len(data) == 0 || data[len(data)-1] == 0 {
+		// no delimiting bit
+		return nil, 0, ErrInvalidLength
+	}
+	last := data[len(data)-1]
+	delimiter := 7
+	for last>>delimiter == 0 {
+		delimiter--
+	}
+	length := (len(data)-1)*8 + delimiter
+	if length > MaxGroupSize {
+		return nil, 0, ErrIndexRange
+	}
+	mask := new(big.Int)
+	for i := 0; i < length; i++ {
+		if data[i/8]>>(i%8)&1 != 0 {
+			mask.SetBit(mask, i, 1)
+		}
+	}
+	return mask, length, nil
+}
+
+// packChunks splits the data into 32-byte chunks padded with zeros
+func packChunks(data []byte) [][32]byte {
+	res := make([][32]byte, (len(data)+sszChunkSize-1)/sszChunkSize)
+	for i := range res {
+		copy(res[i][:], data[i*sszChunkSize:])
+	}
+	return res
+}
+
+// merkleize returns the root of the Merkle tree of the chunks padded with
+// zero chunks up to the next power of two of the limit
+func merkleize(chunks [][32]byte, limit int) [32]byte {
+	depth := 0
+	for 1<<depth < limit {
+		depth++
+	}
+	layer := append([][32]byte{}, chunks...)
+	for level := 0; level < depth; level++ {
+		if len(layer)%2 == 1 {
+			layer = append(layer, zeroHashes[level])
+		}
+		next := make([][32]byte, len(layer)/2)
+		for i := range next {
+			next[i] = hashPair(layer[2*i], layer[2*i+1])
+		}
+		layer = next
+	}
+	if len(layer) == 0 {
+		return zeroHashes[depth]
+	}
+	return layer[0]
+}
+
+// mixInLength hashes the root with the length of the list
+func mixInLength(root [32]byte, length int) [32]byte {
+	var chunk [32]byte
+	binary.LittleEndian.PutUint64(chunk[:], uint64(length))
+	return hashPair(root, chunk)
+}
+
+func hashPair(a, b [32]byte) [32]byte {
+	return sha256.Sum256(append(a[:], b[:]...))
+}
@@ -0,0 +1,196 @@
+package bls
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// WeightedGroup is the group of members with unequal weights, e.g. stakes.
+// A multisignature of the group is valid if the total weight of its signers
+// reaches the threshold.
+type WeightedGroup struct {
+	group     *Group
+	weights   []*big.Int
+	threshold *big.Int
+}
+
+// NewWeightedGroup creates the group of the public keys with the weights.
+// The membership keys are set up as for Group. The weights, their total and
+// the threshold must fit uint256.
+func NewWeightedGroup(pubs []PublicKey, weights []*big.Int, threshold *big.Int) (*WeightedGroup, error) {
+	if len(pubs) != len(weights) {
+		return nil, ErrLengthMismatch
+	}
+	total := new(big.Int)
+	for _, weight := range weights {
+		if weight == nil || weight.Sign() < 0 {
+			return nil, ErrInvalidWeight
+		}
+		total.Add(total, weight)
+	}
+	if total.BitLen() > 256 {
+		return nil, ErrInvalidWeight
+	}
+	if threshold == nil || threshold.Sign() < 0 || threshold.BitLen() > 256 {
+		return nil, ErrInvalidWeight
+	}
+	group, err := NewGroup(pubs)
+	if err != nil {
+		return nil, err
+	}
+	return &WeightedGroup{
+		group:     group,
+		weights:   copyWeights(weights),
+		threshold: new(big.Int).Set(threshold),
+	}, nil
+}
+
+// Group returns the members of the group
+func (group *WeightedGroup) Group() *Group {
+	return group.group
+}
+
+// AggregatedPublicKey returns the aggregated public key of the group
+func (group *WeightedGroup) AggregatedPublicKey() PublicKey {
+	return group.group.aggPub
+}
+
+// AntiRogueCoefficients returns the anti-rogue coefficients of the members
+// to generate the membership key parts
+func (group *WeightedGroup) AntiRogueCoefficients() []big.Int {
+	return group.group.AntiRogueCoefficients()
+}
+
+// TotalWeight returns the weight of all the members
+func (group *WeightedGroup) TotalWeight() *big.Int {
+	res := new(big.Int)
+	for _, weight := range group.weights {
+		res.Add(res, weight)
+	}
+	return res
+}
+
+// SignedWeight returns the total weight of the members in the mask
+func (group *WeightedGroup) SignedWeight(mask *big.Int) (*big.Int, error) {
+	if mask == nil || mask.Sign() < 0 {
+		return nil, ErrInvalidMask
+	}
+	if mask.BitLen() > group.group.Size() {
+		return nil, ErrIndexRange
+	}
+	res := new(big.Int)
+	for i, weight := range group.weights {
+		if mask.Bit(i) != 0 {
+			res.Add(res, weight)
+		}
+	}
+	return res, nil
+}
+
+// Verify checks that the signers of the multisignature reach the weight
+// threshold and verifies the multisignature of the message
+func (group *WeightedGroup) Verify(multi Multisig, message []byte) error {
+	weight, err := group.SignedWeight(multi.PartMask)
+	if err != nil {
+		return err
+	}
+	if weight.Cmp(group.threshold) < 0 {
+		return ErrInsufficientWeight
+	}
+	return group.group.Verify(multi, message)
+}
+
+// WeightsCommitment returns the commitment to the weights and the threshold
+// of the group, which is stored on-chain and checked by
+// verifyWeightedMultisig:
+//
+// keccak256(aggregated public key || weights || threshold)
+//
+// The weights and the threshold are 32-byte big-endian integers, as packed
+// by abi.encodePacked in Solidity.
+func (group *WeightedGroup) WeightsCommitment() [32]byte {
+	data := group.group.aggPub.Marshal()
+	for _, weight := range group.weights {
+		data = append(data, math.U256Bytes(new(big.Int).Set(weight))...)
+	}
+	data = append(data, math.U256Bytes(new(big.Int).Set(group.threshold))...)
+	var res [32]byte
+	copy(res[:], crypto.Keccak256(data))
+	return res
+}
+
+// weightedVerifierABI is the ABI of the on-chain weighted verifier, see
+// verifyWeightedMultisignature in BlsSignatureTest.sol
+const weightedVerifierABI = `[{"type":"function","name":"verifyWeightedMultisignature","inputs":[
+	{"name":"_aggregatedPublicKey","type":"bytes"},
+	{"name":"_partPublicKey","type":"bytes"},
+	{"name":"_message","type":"bytes"},
+	{"name":"_partSignature","type":"bytes"},
+	{"name":"_signersBitmask","type":"uint256[]"},
+	{"name":"_weights","type":"uint256[]"},
+	{"name":"_threshold","type":"uint256"}],"outputs":[]}]`
+
+var weightedVerifier = mustParseABI(weightedVerifierABI)
+
+// mustParseABI parses the ABI definition, panicking at init if it is invalid
+func mustParseABI(definition string) abi.ABI {
+	res, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		panic("bls: invalid ABI: " + err.Error())
+	}
+	return res
+}
+
+// WeightedCalldata holds the arguments of the on-chain weighted verifier
+// verifyWeightedMultisig in BlsSignatureVerification.sol, besides the stored
+// WeightsCommitment
+type WeightedCalldata struct {
+	AggregatedPublicKey []byte
+	PartPublicKey       []byte
+	Message             []byte
+	PartSignature       []byte
+	SignersBitmask      []*big.Int // 256-bit words, the lowest first
+	Weights             []*big.Int
+	Threshold           *big.Int
+}
+
+// Calldata returns the arguments to verify the multisignature of the
+// message on-chain
+func (group *WeightedGroup) Calldata(multi Multisig, message []byte) WeightedCalldata {
+	return WeightedCalldata{
+		AggregatedPublicKey: group.group.aggPub.Marshal(),
+		PartPublicKey:       multi.PartPublicKey.Marshal(),
+		Message:             message,
+		PartSignature:       multi.PartSignature.Marshal(),
+		SignersBitmask:      MultisigMaskWords(multi.PartMask),
+		Weights:             copyWeights(group.weights),
+		Threshold:           new(big.Int).Set(group.threshold),
+	}
+}
+
+// Pack returns the ABI-encoded call of verifyWeightedMultisignature(bytes,
+// bytes,bytes,bytes,uint256[],uint256[],uint256), including the selector
+func (calldata WeightedCalldata) Pack() ([]byte, error) {
+	return weightedVerifier.Pack("verifyWeightedMultisignature",
+		calldata.AggregatedPublicKey,
+		calldata.PartPublicKey,
+		calldata.Message,
+		calldata.PartSignature,
+		calldata.SignersBitmask,
+		calldata.Weights,
+		calldata.Threshold,
+	)
+}
+
+// copyWeights copies the weights, so that the caller can not change them
+func copyWeights(weights []*big.Int) []*big.Int {
+	res := make([]*big.Int, len(weights))
+	for i, weight := range weights {
+		res[i] = new(big.Int).Set(weight)
+	}
+	return res
+}
@@ -7,6 +7,11 @@ import "./BlsSignatureVerification.sol";
 
 contract BlsSignatureTest is BlsSignatureVerification {
     bool public verified;
+    bytes32 public weightsHash;
+
+    function setWeightsHash(bytes32 _weightsHash) external {
+        weightsHash = _weightsHash;
+    }
 
     function verifySignature(
         bytes calldata _publicKey,  // an E2 point
@@ -28,6 +33,14 @@ contract BlsSignatureTest is BlsSignatureVerification {
         verified = verifyForPoint(pub, decodeE1Point(_message), sig);
     }
 
+    function verifyPossessionProof(
+        bytes calldata _publicKey,  // an E2 point
+        bytes calldata _proof       // an E1 point
+    ) external {
+        E2Point memory pub = decodeE2Point(_publicKey);
+        verified = verifyPossession(pub, decodeE1Point(_proof));
+    }
+
     function verifyMultisignature(
         bytes calldata _aggregatedPublicKey,  // an E2 point
         bytes calldata _partPublicKey,        // an E2 point
@@ -41,6 +54,40 @@ contract BlsSignatureTest is BlsSignatureVerification {
         verified = verifyMultisig(aPub, pPub, _message, pSig, _signersBitmask);
     }
 
+    function verifyMultisignatureWide(
+        bytes calldata _aggregatedPublicKey,  // an E2 point
+        bytes calldata _partPublicKey,        // an E2 point
+        bytes calldata _message,
+        bytes calldata _partSignature,        // an E1 point
+        uint[] calldata _signersBitmask
+    ) external {
+        E2Point memory aPub = decodeE2Point(_aggregatedPublicKey);
+        E2Point memory pPub = decodeE2Point(_partPublicKey);
+        E1Point memory pSig = decodeE1Point(_partSignature);
+        verified = verifyMultisigWide(aPub, pPub, _message, pSig, _signersBitmask);
+    }
+
+    function verifyWeightedMultisignature(
+        bytes memory _aggregatedPublicKey,  // an E2 point
+        bytes memory _partPublicKey,        // an E2 point
+        bytes memory _message,
+        bytes memory _partSignature,        // an E1 point
+        uint[] memory _signersBitmask,
+        uint[] memory _weights,
+        uint _threshold
+    ) external {
+        verified = verifyWeightedMultisig(
+            decodeE2Point(_aggregatedPublicKey),
+            decodeE2Point(_partPublicKey),
+            _message,
+            decodeE1Point(_partSignature),
+            _signersBitmask,
+            _weights,
+            _threshold,
+            weightsHash
+        );
+    }
+
     function verifyAggregatedHash(
         bytes calldata _p,
         uint index
@@ -29,6 +29,9 @@ contract BlsSignatureVerification {
     // Taken from go-ethereum/crypto/bn256/cloudflare/constants.go
     uint256 constant p = 21888242871839275222246405745257275088696311157297823662689037894645226208583;
 
+    // Domain of the proofs of possession, must match possessionDomain in bls/pop.go
+    bytes16 constant POSSESSION_DOMAIN = "BLS_POP_BN256G1_";
+
     /**
      * Checks if BLS signature is valid.
      *
@@ -67,6 +70,20 @@ contract BlsSignatureVerification {
         return pairing(e1points, e2points);
     }
 
+    /**
+     * Checks if the proof of possession of the secret key is valid.
+     *
+     * @param _publicKey Public key whose secret key possession is proved.
+     * @param _proof Signature of the public key under the possession domain.
+     * @return True if the proof is valid.
+     */
+    function verifyPossession(
+        E2Point memory _publicKey,
+        E1Point memory _proof
+    ) internal view returns (bool) {
+        return verify(_publicKey, abi.encodePacked(POSSESSION_DOMAIN, _publicKey.x, _publicKey.y), _proof);
+    }
+
     /**
      * Checks if BLS multisignature is valid.
      *
@@ -83,9 +100,130 @@ contract BlsSignatureVerification {
         bytes memory _message,
         E1Point memory _partSignature,
         uint _signersBitmask
+    ) internal view returns (bool) {
+        E1Point memory sum = sumSigners(_aggregatedPublicKey, _signersBitmask, 0);
+        return checkMultisig(_aggregatedPublicKey, _partPublicKey, _message, _partSignature, sum);
+    }
+
+    /**
+     * Checks if BLS multisignature of a group of more than 256 participants is valid.
+     *
+     * @param _aggregatedPublicKey Sum of all public keys
+     * @param _partPublicKey Sum of participated public keys
+     * @param _message Message that was signed
+     * @param _partSignature Signature over the message
+     * @param _signersBitmask Bitmask of participants split into 256-bit words, the lowest word first
+     * @return True if the message was correctly signed by the given participants.
+     */
+    function verifyMultisigWide(
+        E2Point memory _aggregatedPublicKey,
+        E2Point memory _partPublicKey,
+        bytes memory _message,
+        E1Point memory _partSignature,
+        uint[] memory _signersBitmask
     ) internal view returns (bool) {
         E1Point memory sum = E1Point(0, 0);
-        uint index = 0;
+        for (uint word = 0; word < _signersBitmask.length; word++) {
+            sum = addCurveE1(sum, sumSigners(_aggregatedPublicKey, _signersBitmask[word], word * 256));
+        }
+        return checkMultisig(_aggregatedPublicKey, _partPublicKey, _message, _partSignature, sum);
+    }
+
+    /**
+     * Checks if BLS multisignature of a weighted group is valid and the total
+     * weight of its signers reaches the threshold. The weights and the
+     * threshold must be those of the group: their commitment must match the
+     * stored one, see weightsCommitment.
+     *
+     * @param _aggregatedPublicKey Sum of all public keys
+     * @param _partPublicKey Sum of participated public keys
+     * @param _message Message that was signed
+     * @param _partSignature Signature over the message
+     * @param _signersBitmask Bitmask of participants split into 256-bit words, the lowest word first
+     * @param _weights Weights of all participants by index
+     * @param _threshold Minimum total weight of participants
+     * @param _weightsHash Stored commitment to the weights and the threshold of the group
+     * @return True if the message was correctly signed by enough weight of participants.
+     */
+    function verifyWeightedMultisig(
+        E2Point memory _aggregatedPublicKey,
+        E2Point memory _partPublicKey,
+        bytes memory _message,
+        E1Point memory _partSignature,
+        uint[] memory _signersBitmask,
+        uint[] memory _weights,
+        uint _threshold,
+        bytes32 _weightsHash
+    ) internal view returns (bool) {
+        if (weightsCommitment(_aggregatedPublicKey, _weights, _threshold) != _weightsHash) {
+            return false;
+        }
+        (uint weight, bool valid) = signedWeight(_signersBitmask, _weights);
+        if (!valid || weight < _threshold) {
+            return false;
+        }
+        return verifyMultisigWide(_aggregatedPublicKey, _partPublicKey, _message, _partSignature, _signersBitmask);
+    }
+
+    /**
+     * Commits to the weights and the threshold of the group with the given
+     * aggregated public key, as WeightedGroup.WeightsCommitment in Go does.
+     *
+     * @return Hash of the aggregated public key, the weights and the threshold
+     */
+    function weightsCommitment(
+        E2Point memory _aggregatedPublicKey,
+        uint[] memory _weights,
+        uint _threshold
+    ) internal pure returns (bytes32) {
+        return keccak256(abi.encodePacked(
+            _aggregatedPublicKey.x,
+            _aggregatedPublicKey.y,
+            _weights,
+            _threshold
+        ));
+    }
+
+    /**
+     * Sums the weights of participants in the bitmask.
+     *
+     * @return weight Total weight of participants
+     * @return valid False if the bitmask has participants without weights or the sum overflows
+     */
+    function signedWeight(
+        uint[] memory _signersBitmask,
+        uint[] memory _weights
+    ) private pure returns (uint weight, bool valid) {
+        for (uint word = 0; word < _signersBitmask.length; word++) {
+            uint mask = _signersBitmask[word];
+            for (uint index = word * 256; mask != 0; index++) {
+                if (mask & 1 != 0) {
+                    if (index >= _weights.length) {
+                        return (0, false);
+                    }
+                    uint sum = weight + _weights[index];
+                    if (sum < weight) {
+                        // overflow, which is not checked before Solidity 0.8
+                        return (0, false);
+                    }
+                    weight = sum;
+                }
+                mask >>= 1;
+            }
+        }
+        return (weight, true);
+    }
+
+    /**
+     * Sums the hashes of the aggregated public key and the indices of the
+     * participants in the bitmask word starting at the given index.
+     */
+    function sumSigners(
+        E2Point memory _aggregatedPublicKey,
+        uint _signersBitmask,
+        uint _offset
+    ) private view returns (E1Point memory sum) {
+        uint index = _offset;
         uint mask = 1;
         while (_signersBitmask != 0) {
             if (_signersBitmask & mask != 0) {
@@ -95,12 +233,24 @@ contract BlsSignatureVerification {
             mask <<= 1;
             index ++;
         }
+    }
 
+    /**
+     * Checks the pairing equation of BLS multisignature against the sum of
+     * the hashes of participants' indices.
+     */
+    function checkMultisig(
+        E2Point memory _aggregatedPublicKey,
+        E2Point memory _partPublicKey,
+        bytes memory _message,
+        E1Point memory _partSignature,
+        E1Point memory _signersSum
+    ) private view returns (bool) {
         E1Point[] memory e1points = new E1Point[](3);
         E2Point[] memory e2points = new E2Point[](3);
         e1points[0] = negate(_partSignature);
         e1points[1] = hashToCurveE1(abi.encodePacked(_aggregatedPublicKey.x, _aggregatedPublicKey.y, _message));
-        e1points[2] = sum;
+        e1points[2] = _signersSum;
         e2points[0] = G2();
         e2points[1] = _partPublicKey;
         e2points[2] = _aggregatedPublicKey;
@@ -0,0 +1,442 @@
+// Package dkg implements distributed generation of a threshold BLS key
+// (Joint-Feldman/Pedersen DKG) without a trusted dealer. Every participant
+// deals the shares of its own random secret, the group private key is the
+// sum of the secrets of the qualified dealers and is never known to anyone.
+//
+// The same protocol reshares the key from one committee to another, or
+// refreshes the shares of the committee, keeping the group public key.
+//
+// Node is a pure state machine: it consumes and produces messages and does
+// no networking. The transport must deliver the Broadcasts of commitments,
+// complaints and justifications by reliable broadcast, so that all the
+// honest nodes see the same ones, and the Deals of shares by private
+// channels.
+//
+// The protocol runs in phases:
+//  1. every node broadcasts the commitments to its polynomial and sends its
+//     Deals, and processes the broadcasts and the deals of others,
+//  2. every node broadcasts its Complaints against missing or invalid deals,
+//  3. the accused dealers answer the complaints by Justifications revealing
+//     the disputed shares,
+//  4. every node calls Finalize to get the qualified set, the group public
+//     key and its own share.
+package dkg
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"sort"
+
+	"github.com/eywa-protocol/bls-crypto/bls"
+	"github.com/eywa-protocol/bls-crypto/threshold"
+)
+
+var (
+	ErrInvalidConfig           = errors.New("dkg: invalid config")
+	ErrInvalidShare            = errors.New("dkg: share does not match the commitments")
+	ErrUnknownParticipant      = errors.New("dkg: unknown participant")
+	ErrWrongRecipient          = errors.New("dkg: message is addressed to another participant")
+	ErrNotRecipient            = errors.New("dkg: participant gets no share")
+	ErrDuplicateDeal           = errors.New("dkg: duplicate deal")
+	ErrDuplicateBroadcast      = errors.New("dkg: duplicate broadcast")
+	ErrInvalidComplaint        = errors.New("dkg: participant complains against itself")
+	ErrUnexpectedJustification = errors.New("dkg: justification of no complaint")
+	ErrMissingShare            = errors.New("dkg: no valid share from a qualified dealer")
+	ErrNotEnoughQualified      = errors.New("dkg: not enough qualified dealers")
+)
+
+// Config describes the participant and its group
+type Config struct {
+	Index        uint32   // index of the participant, non-zero
+	Participants []uint32 // indices of all the participants including itself
+	Threshold    int      // number of shares needed to sign
+}
+
+func (config Config) validate() error {
+	if config.Threshold < 1 || config.Threshold > len(config.Participants) {
+		return ErrInvalidConfig
+	}
+	if !validIndices(config.Participants) || !contains(config.Participants, config.Index) {
+		return ErrInvalidConfig
+	}
+	return nil
+}
+
+// Broadcast is the message of the dealer to everyone: the commitments to
+// its polynomial. The shares of all the recipients are checked against the
+// same commitments, so a dealer can not deal inconsistent shares.
+type Broadcast struct {
+	Dealer      uint32
+	Commitments threshold.Commitments
+}
+
+// Deal is the private message of the dealer to the recipient: the share of
+// the recipient
+type Deal struct {
+	Dealer    uint32
+	Recipient uint32
+	Share     bls.PrivateKey
+}
+
+// Complaint is broadcast by the complainer who got no deal or an invalid
+// share from the dealer
+type Complaint struct {
+	Complainer uint32
+	Dealer     uint32
+}
+
+// Justification is broadcast by the dealer in answer to the complaint: the
+// share of the complainer revealed to everyone
+type Justification struct {
+	Dealer     uint32
+	Complainer uint32
+	Share      bls.PrivateKey
+}
+
+// Result is the outcome of the key generation
+type Result struct {
+	Qualified   []uint32              // indices of the qualified dealers
+	Commitments threshold.Commitments // commitments to the group polynomial
+	Share       threshold.Share       // share of the participant
+}
+
+// PublicKey returns the group public key
+func (result Result) PublicKey() bls.PublicKey {
+	return result.Commitments.PublicKey()
+}
+
+// Node is the state of a participant of the key generation or resharing
+type Node struct {
+	index          uint32
+	dealers        []uint32
+	recipients     []uint32
+	threshold      int
+	oldCommitments threshold.Commitments            // commitments to the shared key, nil for the key generation
+	poly           threshold.Polynomial             // nil if the node is not a dealer
+	commitments    map[uint32]threshold.Commitments // by dealer, valid ones only
+	received       map[uint32]bls.PrivateKey        // by dealer, unchecked shares
+	shares         map[uint32]bls.PrivateKey        // by dealer, valid ones only
+	broadcast      map[uint32]bool                  // dealers whose broadcasts were processed
+	dealt          map[uint32]bool                  // dealers whose deals were processed
+	complaints     map[uint32]map[uint32]bool       // dealer -> complainer -> justified
+	disqualified   map[uint32]bool
+}
+
+// NewNode creates the participant of the key generation with a fresh
+// random secret
+func NewNode(config Config) (*Node, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	secret, _ := bls.GenerateRandomKey()
+	node := newNode(config.Index, config.Participants, config.Participants, config.Threshold, nil)
+	if err := node.deal(secret); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+func newNode(index uint32, dealers, recipients []uint32, thresh int, oldCommitments threshold.Commitments) *Node {
+	return &Node{
+		index:          index,
+		dealers:        dealers,
+		recipients:     recipients,
+		threshold:      thresh,
+		oldCommitments: oldCommitments,
+		commitments:    make(map[uint32]threshold.Commitments),
+		received:       make(map[uint32]bls.PrivateKey),
+		shares:         make(map[uint32]bls.PrivateKey),
+		broadcast:      make(map[uint32]bool),
+		dealt:          make(map[uint32]bool),
+		complaints:     make(map[uint32]map[uint32]bool),
+		disqualified:   make(map[uint32]bool),
+	}
+}
+
+// deal makes the node the dealer of the secret
+func (node *Node) deal(secret bls.PrivateKey) error {
+	poly, err := threshold.NewPolynomial(secret, node.threshold)
+	if err != nil {
+		return err
+	}
+	node.poly = poly
+	node.commitments[node.index] = poly.Commitments()
+	node.broadcast[node.index] = true
+	node.dealt[node.index] = true
+	if contains(node.recipients, node.index) {
+		own, err := poly.Share(node.index)
+		if err != nil {
+			return err
+		}
+		node.shares[node.index] = own.Key
+	}
+	return nil
+}
+
+// Index returns the index of the participant
+func (node *Node) Index() uint32 {
+	return node.index
+}
+
+// Broadcast returns the commitments of the node to broadcast to all the
+// other participants, nil if the node is not a dealer
+func (node *Node) Broadcast() *Broadcast {
+	if node.poly == nil {
+		return nil
+	}
+	return &Broadcast{Dealer: node.index, Commitments: append(threshold.Commitments{}, node.commitments[node.index]...)}
+}
+
+// ProcessBroadcast checks the commitments of the dealer. Invalid
+// commitments are not an error: everyone sees the same broadcast, so the
+// dealer is disqualified by all.
+func (node *Node) ProcessBroadcast(broadcast Broadcast) error {
+	if !contains(node.dealers, broadcast.Dealer) {
+		return ErrUnknownParticipant
+	}
+	if node.broadcast[broadcast.Dealer] {
+		return ErrDuplicateBroadcast
+	}
+	node.broadcast[broadcast.Dealer] = true
+	if !node.validCommitments(broadcast.Dealer, broadcast.Commitments) {
+		node.disqualified[broadcast.Dealer] = true
+		return nil
+	}
+	node.commitments[broadcast.Dealer] = append(threshold.Commitments{}, broadcast.Commitments...)
+	node.checkShare(broadcast.Dealer)
+	return nil
+}
+
+// Deals returns the deals of the node to all the other recipients, none if
+// the node is not a dealer
+func (node *Node) Deals() ([]Deal, error) {
+	if node.poly == nil {
+		return nil, nil
+	}
+	deals := make([]Deal, 0, len(node.recipients))
+	for _, index := range node.recipients {
+		if index == node.index {
+			continue
+		}
+		share, err := node.poly.Share(index)
+		if err != nil {
+			return nil, err
+		}
+		deals = append(deals, Deal{Dealer: node.index, Recipient: index, Share: share.Key})
+	}
+	return deals, nil
+}
+
+// ProcessDeal checks the deal addressed to the node against the broadcast
+// commitments of the dealer, which may come before or after it. An invalid
+// deal is not an error: it is reported by Complaints.
+func (node *Node) ProcessDeal(deal Deal) error {
+	if deal.Recipient != node.index || !contains(node.recipients, node.index) {
+		return ErrWrongRecipient
+	}
+	if !contains(node.dealers, deal.Dealer) {
+		return ErrUnknownParticipant
+	}
+	if node.dealt[deal.Dealer] {
+		return ErrDuplicateDeal
+	}
+	node.dealt[deal.Dealer] = true
+	node.received[deal.Dealer] = deal.Share
+	node.checkShare(deal.Dealer)
+	return nil
+}
+
+// checkShare keeps the share received from the dealer if it matches the
+// commitments of the dealer
+func (node *Node) checkShare(dealer uint32) {
+	commitments, ok := node.commitments[dealer]
+	if !ok {
+		return
+	}
+	key, ok := node.received[dealer]
+	if !ok {
+		return
+	}
+	delete(node.received, dealer)
+	if commitments.VerifyShare(threshold.Share{Index: node.index, Key: key}) {
+		node.shares[dealer] = key
+	}
+}
+
+// Complaints returns the complaints of the node against the dealers whose
+// deals are missing or invalid
+func (node *Node) Complaints() []Complaint {
+	if !contains(node.recipients, node.index) {
+		return nil
+	}
+	var res []Complaint
+	for _, index := range node.dealers {
+		if _, ok := node.shares[index]; !ok && !node.disqualified[index] {
+			res = append(res, Complaint{Complainer: node.index, Dealer: index})
+		}
+	}
+	return res
+}
+
+// ProcessComplaint records the complaint. If the node is the accused
+// dealer, it returns the justification to broadcast.
+func (node *Node) ProcessComplaint(complaint Complaint) (*Justification, error) {
+	if !contains(node.dealers, complaint.Dealer) || !contains(node.recipients, complaint.Complainer) {
+		return nil, ErrUnknownParticipant
+	}
+	if complaint.Dealer == complaint.Complainer {
+		return nil, ErrInvalidComplaint
+	}
+	if node.complaints[complaint.Dealer] == nil {
+		node.complaints[complaint.Dealer] = make(map[uint32]bool)
+	}
+	node.complaints[complaint.Dealer][complaint.Complainer] = false
+	if complaint.Dealer != node.index || node.poly == nil {
+		return nil, nil
+	}
+	share, err := node.poly.Share(complaint.Complainer)
+	if err != nil {
+		return nil, err
+	}
+	return &Justification{Dealer: node.index, Complainer: complaint.Complainer, Share: share.Key}, nil
+}
+
+// ProcessJustification checks the revealed share against the broadcast
+// commitments of the dealer, disqualifying the dealer if it is invalid
+func (node *Node) ProcessJustification(justification Justification) error {
+	complainers, ok := node.complaints[justification.Dealer]
+	if !ok {
+		return ErrUnexpectedJustification
+	}
+	if _, ok := complainers[justification.Complainer]; !ok {
+		return ErrUnexpectedJustification
+	}
+	commitments, ok := node.commitments[justification.Dealer]
+	share := threshold.Share{Index: justification.Complainer, Key: justification.Share}
+	if !ok || !commitments.VerifyShare(share) {
+		node.disqualified[justification.Dealer] = true
+		return nil
+	}
+	complainers[justification.Complainer] = true
+	if justification.Complainer == node.index {
+		node.shares[justification.Dealer] = justification.Share
+	}
+	return nil
+}
+
+// Finalize completes the protocol. The qualified dealers are those with
+// valid broadcast commitments and all the complaints against them
+// justified. The
+// members of the old committee leaving it get ErrNotRecipient.
+func (node *Node) Finalize() (*Result, error) {
+	if !contains(node.recipients, node.index) {
+		return nil, ErrNotRecipient
+	}
+	var qualified []uint32
+	for _, index := range node.dealers {
+		if node.isQualified(index) {
+			qualified = append(qualified, index)
+		}
+	}
+	required := node.threshold
+	if node.oldCommitments != nil {
+		required = node.oldCommitments.Threshold()
+	}
+	if len(qualified) < required {
+		return nil, ErrNotEnoughQualified
+	}
+	sort.Slice(qualified, func(i, j int) bool { return qualified[i] < qualified[j] })
+
+	// The new polynomial is the sum of the polynomials of the dealers, or
+	// their Lagrange interpolation at zero when resharing
+	coefs := make([]big.Int, len(qualified))
+	if node.oldCommitments == nil {
+		for i := range coefs {
+			coefs[i].SetInt64(1)
+		}
+	} else {
+		var err error
+		if coefs, err = threshold.LagrangeCoefficients(qualified); err != nil {
+			return nil, err
+		}
+	}
+
+	commitments := make(threshold.Commitments, node.threshold)
+	for i := range commitments {
+		commitments[i] = bls.ZeroPublicKey()
+	}
+	sum := new(big.Int)
+	for i, index := range qualified {
+		for j, commitment := range node.commitments[index] {
+			commitments[j] = commitments[j].Aggregate(commitment.ScalarMult(&coefs[i]))
+		}
+		share, ok := node.shares[index]
+		if !ok {
+			return nil, ErrMissingShare
+		}
+		sum.Add(sum, share.Scalar().Mul(share.Scalar(), &coefs[i]))
+	}
+	key, err := bls.PrivateKeyFromScalar(sum.Mod(sum, bls.Order()))
+	if err != nil {
+		return nil, err
+	}
+	return &Result{
+		Qualified:   qualified,
+		Commitments: commitments,
+		Share:       threshold.Share{Index: node.index, Key: key},
+	}, nil
+}
+
+// validCommitments checks the commitments of the dealer: they must be of
+// the new threshold, valid points other than infinity and, when resharing,
+// commit to the old share of the dealer
+func (node *Node) validCommitments(dealer uint32, commitments threshold.Commitments) bool {
+	if len(commitments) != node.threshold {
+		return false
+	}
+	for _, commitment := range commitments {
+		// ParsePublicKey rejects the nil points and the point at infinity
+		if _, err := bls.ParsePublicKey(commitment.Marshal()); err != nil {
+			return false
+		}
+	}
+	if node.oldCommitments == nil {
+		return true
+	}
+	expected := node.oldCommitments.SharePublicKey(dealer)
+	return bytes.Equal(commitments.PublicKey().Marshal(), expected.Marshal())
+}
+
+func (node *Node) isQualified(dealer uint32) bool {
+	if _, ok := node.commitments[dealer]; !ok || node.disqualified[dealer] {
+		return false
+	}
+	for _, justified := range node.complaints[dealer] {
+		if !justified {
+			return false
+		}
+	}
+	return true
+}
+
+// validIndices checks that the indices are non-zero and distinct
+func validIndices(indices []uint32) bool {
+	seen := make(map[uint32]struct{}, len(indices))
+	for _, index := range indices {
+		if _, ok := seen[index]; ok || index == 0 {
+			return false
+		}
+		seen[index] = struct{}{}
+	}
+	return true
+}
+
+func contains(indices []uint32, index uint32) bool {
+	for _, i := range indices {
+		if i == index {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,72 @@
+package dkg
+
+import (
+	"github.com/eywa-protocol/bls-crypto/threshold"
+)
+
+// ReshareConfig describes the participant of resharing the group key from
+// the old committee to the new one. Every member of the old committee deals
+// its share, and the new shares are interpolated from the deals of at least
+// the old threshold of qualified dealers.
+type ReshareConfig struct {
+	Index           uint32                // index of the participant in either committee
+	OldParticipants []uint32              // indices of the old committee
+	OldCommitments  threshold.Commitments // commitments to the group polynomial of the old committee
+	Share           *threshold.Share      // share of the old committee, nil for new members
+	NewParticipants []uint32              // indices of the new committee
+	NewThreshold    int                   // number of new shares needed to sign
+}
+
+func (config ReshareConfig) validate() error {
+	if len(config.OldCommitments) == 0 || len(config.OldCommitments) > len(config.OldParticipants) {
+		return ErrInvalidConfig
+	}
+	if config.NewThreshold < 1 || config.NewThreshold > len(config.NewParticipants) {
+		return ErrInvalidConfig
+	}
+	if !validIndices(config.OldParticipants) || !validIndices(config.NewParticipants) {
+		return ErrInvalidConfig
+	}
+	if !contains(config.OldParticipants, config.Index) && !contains(config.NewParticipants, config.Index) {
+		return ErrInvalidConfig
+	}
+	if config.Share != nil {
+		if config.Share.Index != config.Index || !contains(config.OldParticipants, config.Index) {
+			return ErrInvalidConfig
+		}
+		if !config.OldCommitments.VerifyShare(*config.Share) {
+			return ErrInvalidShare
+		}
+	}
+	return nil
+}
+
+// NewReshareNode creates the participant of resharing. The result has new
+// shares and commitments, but the same group public key.
+func NewReshareNode(config ReshareConfig) (*Node, error) {
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
+	node := newNode(config.Index, config.OldParticipants, config.NewParticipants, config.NewThreshold, config.OldCommitments)
+	if config.Share != nil {
+		if err := node.deal(config.Share.Key); err != nil {
+			return nil, err
+		}
+	}
+	return node, nil
+}
+
+// NewRefreshNode creates the participant of refreshing the shares of the
+// same committee with the same threshold. The refreshed shares can not be
+// combined with the former ones, so the shares leaked before the refresh
+// become useless.
+func NewRefreshNode(share threshold.Share, participants []uint32, commitments threshold.Commitments) (*Node, error) {
+	return NewReshareNode(ReshareConfig{
+		Index:           share.Index,
+		OldParticipants: participants,
+		OldCommitments:  commitments,
+		Share:           &share,
+		NewParticipants: participants,
+		NewThreshold:    commitments.Threshold(),
+	})
+}
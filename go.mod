@@ -4,6 +4,9 @@ go 1.16
 
 require (
 	github.com/ethereum/go-ethereum v1.10.8
+	github.com/google/uuid v1.1.5
 	github.com/keep-network/keep-core v1.3.1
 	github.com/stretchr/testify v1.7.0
+	golang.org/x/crypto v0.0.0-20210322153248-0c34fe9e7dc2
+	golang.org/x/text v0.3.6
 )
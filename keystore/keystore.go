@@ -0,0 +1,366 @@
+// Package keystore implements EIP-2335 style JSON keystores for BLS private
+// keys: the key is encrypted by AES-128-CTR under a scrypt or PBKDF2 derived
+// key, and the decryption key is checked against a SHA-256 checksum.
+package keystore
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/eywa-protocol/bls-crypto/bls"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	Version = 4
+
+	KDFScrypt = "scrypt"
+	KDFPBKDF2 = "pbkdf2"
+
+	checksumFunction = "sha256"
+	cipherFunction   = "aes-128-ctr"
+	pbkdf2PRF        = "hmac-sha256"
+	secretSize       = 32
+	saltSize         = 32
+	dklen            = 32
+)
+
+// Limits of the KDF parameters, which are read from the untrusted keystore:
+// at most 1GB of memory and r⋅p = 64 for scrypt, 2^22 iterations of PBKDF2
+const (
+	maxScryptN      = 1 << 20
+	maxScryptMemory = 1 << 30 // 128⋅r⋅N bytes
+	maxScryptRP     = 64
+	maxPBKDF2C      = 1 << 22
+)
+
+var (
+	// ErrInvalidPassword is returned when the checksum does not match the
+	// key derived from the password
+	ErrInvalidPassword = errors.New("keystore: invalid password")
+	// ErrInvalidKeystore is returned (wrapped) when the keystore is corrupted
+	// or uses an unsupported format
+	ErrInvalidKeystore = errors.New("keystore: invalid keystore")
+)
+
+// Keystore is a JSON keystore holding an encrypted BLS private key
+type Keystore struct {
+	Crypto      Crypto `json:"crypto"`
+	Description string `json:"description"`
+	Pubkey      string `json:"pubkey"`
+	Path        string `json:"path"`
+	UUID        string `json:"uuid"`
+	Version     int    `json:"version"`
+}
+
+// Crypto describes the key derivation, the checksum and the cipher modules
+type Crypto struct {
+	KDF      Module `json:"kdf"`
+	Checksum Module `json:"checksum"`
+	Cipher   Module `json:"cipher"`
+}
+
+// Module is a single step of the keystore decryption
+type Module struct {
+	Function string          `json:"function"`
+	Params   json.RawMessage `json:"params"`
+	Message  string          `json:"message"`
+}
+
+type scryptParams struct {
+	Dklen int    `json:"dklen"`
+	N     int    `json:"n"`
+	P     int    `json:"p"`
+	R     int    `json:"r"`
+	Salt  string `json:"salt"`
+}
+
+type pbkdf2Params struct {
+	Dklen int    `json:"dklen"`
+	C     int    `json:"c"`
+	Prf   string `json:"prf"`
+	Salt  string `json:"salt"`
+}
+
+type cipherParams struct {
+	IV string `json:"iv"`
+}
+
+// Options controls the key derivation and the metadata of a new keystore
+type Options struct {
+	KDF         string // KDFScrypt or KDFPBKDF2
+	ScryptN     int
+	ScryptR     int
+	ScryptP     int
+	PBKDF2C     int
+	Path        string // derivation path of the key, if any
+	Description string
+}
+
+var (
+	// DefaultScryptOptions are the scrypt parameters recommended by EIP-2335
+	DefaultScryptOptions = Options{KDF: KDFScrypt, ScryptN: 1 << 18, ScryptR: 8, ScryptP: 1}
+	// DefaultPBKDF2Options are the PBKDF2 parameters recommended by EIP-2335
+	DefaultPBKDF2Options = Options{KDF: KDFPBKDF2, PBKDF2C: 1 << 18}
+)
+
+// Encrypt creates a keystore protecting the private key with the password
+func Encrypt(priv bls.PrivateKey, password string, opts Options) (*Keystore, error) {
+	secret, err := secretBytes(priv)
+	if err != nil {
+		return nil, err
+	}
+
+	salt := make([]byte, saltSize)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, err
+	}
+	iv := make([]byte, aes.BlockSize)
+	if _, err := io.ReadFull(rand.Reader, iv); err != nil {
+		return nil, err
+	}
+
+	var kdf Module
+	switch opts.KDF {
+	case KDFScrypt:
+		kdf, err = newModule(KDFScrypt, scryptParams{
+			Dklen: dklen, N: opts.ScryptN, P: opts.ScryptP, R: opts.ScryptR, Salt: hex.EncodeToString(salt),
+		})
+	case KDFPBKDF2:
+		kdf, err = newModule(KDFPBKDF2, pbkdf2Params{
+			Dklen: dklen, C: opts.PBKDF2C, Prf: pbkdf2PRF, Salt: hex.EncodeToString(salt),
+		})
+	default:
+		return nil, fmt.Errorf("keystore: unsupported kdf %q", opts.KDF)
+	}
+	if err != nil {
+		return nil, err
+	}
+	ks := &Keystore{
+		Crypto:      Crypto{KDF: kdf},
+		Description: opts.Description,
+		Pubkey:      hex.EncodeToString(priv.PublicKey().Marshal()),
+		Path:        opts.Path,
+		UUID:        uuid.New().String(),
+		Version:     Version,
+	}
+
+	key, err := ks.deriveKey(password)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := aesCTR(key[:16], iv, secret)
+	if err != nil {
+		return nil, err
+	}
+	if ks.Crypto.Cipher, err = newModule(cipherFunction, cipherParams{IV: hex.EncodeToString(iv)}); err != nil {
+		return nil, err
+	}
+	ks.Crypto.Cipher.Message = hex.EncodeToString(ciphertext)
+	if ks.Crypto.Checksum, err = newModule(checksumFunction, struct{}{}); err != nil {
+		return nil, err
+	}
+	ks.Crypto.Checksum.Message = hex.EncodeToString(checksum(key, ciphertext))
+	return ks, nil
+}
+
+// Decrypt recovers the private key. It returns ErrInvalidPassword if the
+// password is wrong and an error wrapping ErrInvalidKeystore if the keystore
+// is corrupted.
+func (ks *Keystore) Decrypt(password string) (bls.PrivateKey, error) {
+	if ks.Version != Version {
+		return bls.PrivateKey{}, invalidf("unsupported version %d", ks.Version)
+	}
+	if ks.Crypto.Checksum.Function != checksumFunction {
+		return bls.PrivateKey{}, invalidf("unsupported checksum %q", ks.Crypto.Checksum.Function)
+	}
+	if ks.Crypto.Cipher.Function != cipherFunction {
+		return bls.PrivateKey{}, invalidf("unsupported cipher %q", ks.Crypto.Cipher.Function)
+	}
+	var params cipherParams
+	if err := json.Unmarshal(ks.Crypto.Cipher.Params, &params); err != nil {
+		return bls.PrivateKey{}, invalidf("cipher params: %v", err)
+	}
+	iv, err := hex.DecodeString(params.IV)
+	if err != nil || len(iv) != aes.BlockSize {
+		return bls.PrivateKey{}, invalidf("cipher iv")
+	}
+	ciphertext, err := hex.DecodeString(ks.Crypto.Cipher.Message)
+	if err != nil || len(ciphertext) != secretSize {
+		return bls.PrivateKey{}, invalidf("cipher message")
+	}
+	sum, err := hex.DecodeString(ks.Crypto.Checksum.Message)
+	if err != nil || len(sum) != sha256.Size {
+		return bls.PrivateKey{}, invalidf("checksum message")
+	}
+
+	key, err := ks.deriveKey(password)
+	if err != nil {
+		return bls.PrivateKey{}, err
+	}
+	if !bytes.Equal(checksum(key, ciphertext), sum) {
+		return bls.PrivateKey{}, ErrInvalidPassword
+	}
+	secret, err := aesCTR(key[:16], iv, ciphertext)
+	if err != nil {
+		return bls.PrivateKey{}, err
+	}
+	priv, err := bls.ReadPrivateKey(hex.EncodeToString(secret))
+	if err != nil {
+		return bls.PrivateKey{}, invalidf("secret: %v", err)
+	}
+
+	if ks.Pubkey != "" {
+		pub, err := ks.PublicKey()
+		if err != nil {
+			return bls.PrivateKey{}, err
+		}
+		if !bytes.Equal(priv.PublicKey().Marshal(), pub.Marshal()) {
+			return bls.PrivateKey{}, invalidf("pubkey does not match the secret")
+		}
+	}
+	return priv, nil
+}
+
+// PublicKey returns the public key stored in the keystore in plaintext
+func (ks *Keystore) PublicKey() (bls.PublicKey, error) {
+	pub, err := bls.ReadPublicKey(ks.Pubkey)
+	if err != nil {
+		return bls.PublicKey{}, invalidf("pubkey: %v", err)
+	}
+	return pub, nil
+}
+
+// Marshal encodes the keystore to JSON
+func (ks *Keystore) Marshal() ([]byte, error) {
+	return json.MarshalIndent(ks, "", "  ")
+}
+
+// Unmarshal decodes the keystore from JSON
+func Unmarshal(data []byte) (*Keystore, error) {
+	var ks Keystore
+	if err := json.Unmarshal(data, &ks); err != nil {
+		return nil, invalidf("%v", err)
+	}
+	if ks.Version != Version {
+		return nil, invalidf("unsupported version %d", ks.Version)
+	}
+	return &ks, nil
+}
+
+// deriveKey runs the KDF module on the password
+func (ks *Keystore) deriveKey(password string) ([]byte, error) {
+	pass := processPassword(password)
+	kdf := ks.Crypto.KDF
+	switch kdf.Function {
+	case KDFScrypt:
+		var params scryptParams
+		if err := json.Unmarshal(kdf.Params, &params); err != nil {
+			return nil, invalidf("kdf params: %v", err)
+		}
+		salt, err := hex.DecodeString(params.Salt)
+		if err != nil {
+			return nil, invalidf("kdf salt")
+		}
+		if err := params.validate(); err != nil {
+			return nil, err
+		}
+		key, err := scrypt.Key(pass, salt, params.N, params.R, params.P, params.Dklen)
+		if err != nil {
+			return nil, invalidf("kdf: %v", err)
+		}
+		return key, nil
+	case KDFPBKDF2:
+		var params pbkdf2Params
+		if err := json.Unmarshal(kdf.Params, &params); err != nil {
+			return nil, invalidf("kdf params: %v", err)
+		}
+		salt, err := hex.DecodeString(params.Salt)
+		if err != nil {
+			return nil, invalidf("kdf salt")
+		}
+		if params.Prf != pbkdf2PRF {
+			return nil, invalidf("unsupported prf %q", params.Prf)
+		}
+		if params.Dklen != dklen || params.C <= 0 || params.C > maxPBKDF2C {
+			return nil, invalidf("kdf params")
+		}
+		return pbkdf2.Key(pass, salt, params.C, params.Dklen, sha256.New), nil
+	default:
+		return nil, invalidf("unsupported kdf %q", kdf.Function)
+	}
+}
+
+// validate checks the parameters against the limits, so that a crafted
+// keystore can not exhaust the memory or the CPU
+func (params scryptParams) validate() error {
+	if params.Dklen != dklen {
+		return invalidf("kdf dklen %d", params.Dklen)
+	}
+	if params.N <= 1 || params.N > maxScryptN || params.N&(params.N-1) != 0 || params.R <= 0 || params.P <= 0 {
+		return invalidf("kdf params")
+	}
+	if params.R > maxScryptRP || params.P > maxScryptRP || params.R*params.P > maxScryptRP || 128*int64(params.R)*int64(params.N) > maxScryptMemory {
+		return invalidf("kdf params")
+	}
+	return nil
+}
+
+// processPassword strips the control codes from the password as required by
+// EIP-2335. Note that the NFKD normalization is left to the caller.
+func processPassword(password string) []byte {
+	var res []byte
+	for _, r := range password {
+		if r < 0x20 || (r >= 0x7F && r <= 0x9F) {
+			continue
+		}
+		res = append(res, string(r)...)
+	}
+	return res
+}
+
+func checksum(key, ciphertext []byte) []byte {
+	hash := sha256.Sum256(append(append([]byte{}, key[16:32]...), ciphertext...))
+	return hash[:]
+}
+
+func aesCTR(key, iv, data []byte) ([]byte, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	res := make([]byte, len(data))
+	cipher.NewCTR(block, iv).XORKeyStream(res, data)
+	return res, nil
+}
+
+func newModule(function string, params interface{}) (Module, error) {
+	raw, err := json.Marshal(params)
+	if err != nil {
+		return Module{}, err
+	}
+	return Module{Function: function, Params: raw}, nil
+}
+
+// secretBytes returns the private key as a 32-byte big-endian integer
+func secretBytes(priv bls.PrivateKey) ([]byte, error) {
+	secret := priv.Marshal()
+	if _, err := bls.ParsePrivateKey(secret); err != nil {
+		return nil, errors.New("keystore: invalid private key")
+	}
+	return secret, nil
+}
+
+func invalidf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w: "+format, append([]interface{}{ErrInvalidKeystore}, args...)...)
+}
@@ -0,0 +1,168 @@
+// Package mnemonic implements BIP-39 mnemonic sentences to back up BLS
+// private keys on paper and to restore them.
+package mnemonic
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/sha512"
+	_ "embed"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/eywa-protocol/bls-crypto/bls"
+	"golang.org/x/crypto/pbkdf2"
+	"golang.org/x/text/unicode/norm"
+)
+
+// DefaultPath is the derivation path of the signing key restored from a
+// mnemonic
+const DefaultPath = "m/12381/60/0/0"
+
+const (
+	seedIterations = 2048
+	seedSize       = 64
+	bitsPerWord    = 11
+)
+
+var (
+	ErrEntropySize = errors.New("mnemonic: entropy must be 128 to 256 bits, a multiple of 32")
+	ErrWordCount   = errors.New("mnemonic: mnemonic must be 12 to 24 words, a multiple of 3")
+	ErrUnknownWord = errors.New("mnemonic: unknown word")
+	ErrChecksum    = errors.New("mnemonic: invalid checksum")
+)
+
+//go:embed english.txt
+var english string
+
+var (
+	wordlist  = strings.Split(strings.TrimSpace(english), "\n")
+	wordIndex = make(map[string]int, len(wordlist))
+)
+
+func init() {
+	for i, word := range wordlist {
+		wordIndex[word] = i
+	}
+}
+
+// NewEntropy returns the given number of random bits (128, 160, ..., 256)
+func NewEntropy(bits int) ([]byte, error) {
+	if bits < 128 || bits > 256 || bits%32 != 0 {
+		return nil, ErrEntropySize
+	}
+	entropy := make([]byte, bits/8)
+	if _, err := io.ReadFull(rand.Reader, entropy); err != nil {
+		return nil, err
+	}
+	return entropy, nil
+}
+
+// NewMnemonic encodes the entropy with its checksum as a sentence of words
+// from the English wordlist
+func NewMnemonic(entropy []byte) (string, error) {
+	bits := len(entropy) * 8
+	if bits < 128 || bits > 256 || bits%32 != 0 {
+		return "", ErrEntropySize
+	}
+	checksumBits := bits / 32
+	data := append(append([]byte{}, entropy...), checksum(entropy))
+
+	words := make([]string, (bits+checksumBits)/bitsPerWord)
+	for i := range words {
+		index := 0
+		for j := 0; j < bitsPerWord; j++ {
+			index = index<<1 | bit(data, i*bitsPerWord+j)
+		}
+		words[i] = wordlist[index]
+	}
+	return strings.Join(words, " "), nil
+}
+
+// EntropyFromMnemonic decodes the mnemonic and verifies its checksum
+func EntropyFromMnemonic(mnemonic string) ([]byte, error) {
+	words := strings.Fields(mnemonic)
+	if len(words) < 12 || len(words) > 24 || len(words)%3 != 0 {
+		return nil, ErrWordCount
+	}
+	totalBits := len(words) * bitsPerWord
+	checksumBits := totalBits / 33
+	data := make([]byte, (totalBits+7)/8)
+	for i, word := range words {
+		index, ok := wordIndex[word]
+		if !ok {
+			return nil, ErrUnknownWord
+		}
+		for j := 0; j < bitsPerWord; j++ {
+			if index&(1<<(bitsPerWord-1-j)) != 0 {
+				pos := i*bitsPerWord + j
+				data[pos/8] |= 0x80 >> (pos % 8)
+			}
+		}
+	}
+
+	entropy := data[:(totalBits-checksumBits)/8]
+	mask := byte(0xFF) << (8 - checksumBits)
+	if (checksum(entropy)^data[len(entropy)])&mask != 0 {
+		return nil, ErrChecksum
+	}
+	return entropy, nil
+}
+
+// Validate checks the words and the checksum of the mnemonic
+func Validate(mnemonic string) error {
+	_, err := EntropyFromMnemonic(mnemonic)
+	return err
+}
+
+// Seed validates the mnemonic and derives the 64-byte seed from it and the
+// optional passphrase, both normalized to NFKD as BIP-39 requires
+func Seed(mnemonic string, passphrase string) ([]byte, error) {
+	mnemonic = norm.NFKD.String(mnemonic)
+	if err := Validate(mnemonic); err != nil {
+		return nil, err
+	}
+	sentence := strings.Join(strings.Fields(mnemonic), " ")
+	salt := norm.NFKD.String("mnemonic" + passphrase)
+	return pbkdf2.Key([]byte(sentence), []byte(salt), seedIterations, seedSize, sha512.New), nil
+}
+
+// PrivateKey restores the private key derived by the path from the seed of
+// the mnemonic
+func PrivateKey(mnemonic string, passphrase string, path string) (bls.PrivateKey, error) {
+	seed, err := Seed(mnemonic, passphrase)
+	if err != nil {
+		return bls.PrivateKey{}, err
+	}
+	return bls.DeriveKey(seed, path)
+}
+
+// GenerateKey creates a random 24-word mnemonic and the private and public
+// keys at DefaultPath, the recoverable counterpart of bls.GenerateRandomKey
+func GenerateKey(passphrase string) (string, bls.PrivateKey, bls.PublicKey, error) {
+	entropy, err := NewEntropy(256)
+	if err != nil {
+		return "", bls.PrivateKey{}, bls.PublicKey{}, err
+	}
+	mnemonic, err := NewMnemonic(entropy)
+	if err != nil {
+		return "", bls.PrivateKey{}, bls.PublicKey{}, err
+	}
+	priv, err := PrivateKey(mnemonic, passphrase, DefaultPath)
+	if err != nil {
+		return "", bls.PrivateKey{}, bls.PublicKey{}, err
+	}
+	return mnemonic, priv, priv.PublicKey(), nil
+}
+
+// checksum returns the first byte of SHA-256 of the entropy, of which the
+// first len(entropy)/4 bits are used
+func checksum(entropy []byte) byte {
+	hash := sha256.Sum256(entropy)
+	return hash[0]
+}
+
+func bit(data []byte, pos int) int {
+	return int(data[pos/8]>>(7-pos%8)) & 1
+}
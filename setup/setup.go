@@ -0,0 +1,138 @@
+// Package setup implements the interactive setup of membership keys for
+// accountable multisignatures. Every member sends a membership key part to
+// every member (itself included):
+//
+// (Aj⋅pkj)×H(P, i) from member j to member i
+//
+// and every member collects the parts addressed to it into its membership
+// key MKi, checking each one against the public key of the sender.
+package setup
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/eywa-protocol/bls-crypto/bls"
+)
+
+var (
+	ErrUnknownMember  = errors.New("setup: unknown member")
+	ErrWrongRecipient = errors.New("setup: part is addressed to another member")
+	ErrDuplicatePart  = errors.New("setup: duplicate part")
+	ErrInvalidPart    = errors.New("setup: invalid membership key part")
+	ErrIncomplete     = errors.New("setup: not all parts are collected")
+)
+
+// Part is the membership key part sent by the sender to the recipient.
+// Sender and Recipient are the indices of members in the group.
+type Part struct {
+	Sender    uint32
+	Recipient uint32
+	Signature bls.Signature
+}
+
+// GenerateParts generates the membership key parts of the member with the
+// private key for all the members of the group
+func GenerateParts(secretKey bls.PrivateKey, group *bls.Group) ([]Part, error) {
+	sender, ok := group.IndexOf(secretKey.PublicKey())
+	if !ok {
+		return nil, ErrUnknownMember
+	}
+	parts := make([]Part, group.Size())
+	for i := range parts {
+		part, err := group.MembershipKeyPart(secretKey, uint32(i))
+		if err != nil {
+			return nil, err
+		}
+		parts[i] = Part{Sender: sender, Recipient: uint32(i), Signature: part}
+	}
+	return parts, nil
+}
+
+// Collector collects the membership key parts addressed to a member
+type Collector struct {
+	group  *bls.Group
+	index  uint32
+	parts  map[uint32]bls.Signature // valid parts by sender
+	blamed map[uint32]bool          // senders of invalid parts
+}
+
+// NewCollector creates the collector of the member with the given index in
+// the group
+func NewCollector(index uint32, group *bls.Group) (*Collector, error) {
+	if int64(index) >= int64(group.Size()) {
+		return nil, ErrUnknownMember
+	}
+	return &Collector{
+		group:  group,
+		index:  index,
+		parts:  make(map[uint32]bls.Signature, group.Size()),
+		blamed: make(map[uint32]bool),
+	}, nil
+}
+
+// AggregatedPublicKey returns the aggregated public key of the group P
+func (collector *Collector) AggregatedPublicKey() bls.PublicKey {
+	return collector.group.AggregatedPublicKey()
+}
+
+// Add checks the part and adds it to the collected ones. The sender of an
+// invalid part is blamed, and the error wraps ErrInvalidPart.
+func (collector *Collector) Add(part Part) error {
+	if part.Recipient != collector.index {
+		return ErrWrongRecipient
+	}
+	if int64(part.Sender) >= int64(collector.group.Size()) {
+		return ErrUnknownMember
+	}
+	if _, ok := collector.parts[part.Sender]; ok {
+		return ErrDuplicatePart
+	}
+	if !collector.group.VerifyMembershipKeyPart(part.Signature, part.Sender, collector.index) {
+		collector.blamed[part.Sender] = true
+		return fmt.Errorf("%w from member %d", ErrInvalidPart, part.Sender)
+	}
+	collector.parts[part.Sender] = part.Signature
+	return nil
+}
+
+// Done checks whether the parts of all the members are collected
+func (collector *Collector) Done() bool {
+	return len(collector.parts) == collector.group.Size()
+}
+
+// Missing returns the indices of the members whose valid parts are not
+// collected yet
+func (collector *Collector) Missing() []uint32 {
+	var res []uint32
+	for i := 0; i < collector.group.Size(); i++ {
+		if _, ok := collector.parts[uint32(i)]; !ok {
+			res = append(res, uint32(i))
+		}
+	}
+	return res
+}
+
+// Blamed returns the indices of the members who sent invalid parts
+func (collector *Collector) Blamed() []uint32 {
+	res := make([]uint32, 0, len(collector.blamed))
+	for index := range collector.blamed {
+		res = append(res, index)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i] < res[j] })
+	return res
+}
+
+// MembershipKey aggregates the collected parts into the membership key
+// once the parts of all the members are collected
+func (collector *Collector) MembershipKey() (bls.Signature, error) {
+	if !collector.Done() {
+		return bls.Signature{}, ErrIncomplete
+	}
+	res := bls.ZeroSignature()
+	for i := 0; i < collector.group.Size(); i++ {
+		res = res.Aggregate(collector.parts[uint32(i)])
+	}
+	return res, nil
+}
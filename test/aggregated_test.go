@@ -62,9 +62,57 @@ func Test_SignAsPointInSolidity(t *testing.T) {
 }
 
 func Test_AggregatedHashInSolidity(t *testing.T) {
-	index := byte(42)
-	dataBytes, err := blsSignatureTest.VerifyAggregatedHash(&bind.CallOpts{}, aggPub.Marshal(), big.NewInt(int64(index)))
+	for _, index := range []uint32{42, 4242} {
+		dataBytes, err := blsSignatureTest.VerifyAggregatedHash(&bind.CallOpts{}, aggPub.Marshal(), big.NewInt(int64(index)))
+		require.NoError(t, err)
+		res := bls.HashToPointIndex(aggPub, index)
+		require.Equal(t, 0, bytes.Compare(dataBytes, res.Marshal()))
+	}
+}
+
+func Test_AggregateVerifyDistinct(t *testing.T) {
+	N := 5
+	privs, pubs := GenerateRandomKeys(N)
+	msgs := make([][]byte, N)
+	sigs := make([]bls.Signature, N)
+	for i, priv := range privs {
+		msgs[i] = GenRandomBytes(MESSAGE_SIZE)
+		sigs[i] = priv.Sign(msgs[i])
+	}
+	sig, err := bls.AggregateSignaturesDistinct(sigs, msgs)
 	require.NoError(t, err)
-	res := bls.HashToPointIndex(aggPub, index)
-	require.Equal(t, 0, bytes.Compare(dataBytes, res.Marshal()))
+	require.True(t, bls.AggregateVerify(pubs, msgs, sig))
+
+	// Swapped messages, missing signers and foreign signatures fail
+	swapped := append([][]byte{msgs[1], msgs[0]}, msgs[2:]...)
+	require.False(t, bls.AggregateVerify(pubs, swapped, sig))
+	require.False(t, bls.AggregateVerify(pubs[1:], msgs[1:], sig))
+	require.False(t, bls.AggregateVerify(pubs, msgs, sig.Aggregate(privs[0].Sign(msg))))
+	require.False(t, bls.AggregateVerify(pubs, msgs[1:], sig))
+	require.False(t, bls.AggregateVerify(nil, nil, sig))
+
+	_, err = bls.AggregateSignaturesDistinct(sigs, msgs[1:])
+	require.Equal(t, bls.ErrLengthMismatch, err)
+}
+
+func Test_AggregateVerifyDuplicateMessages(t *testing.T) {
+	privs, pubs := GenerateRandomKeys(3)
+	msgs := [][]byte{msg, GenRandomBytes(MESSAGE_SIZE), msg}
+	sigs := make([]bls.Signature, len(privs))
+	for i, priv := range privs {
+		sigs[i] = priv.Sign(msgs[i])
+	}
+	_, err := bls.AggregateSignaturesDistinct(sigs, msgs)
+	require.Equal(t, bls.ErrDuplicateMessage, err)
+	sig := sigs[0].Aggregate(sigs[1]).Aggregate(sigs[2])
+	require.False(t, bls.AggregateVerify(pubs, msgs, sig))
+
+	// Message augmentation allows the same message to be signed
+	augmented := bls.ZeroSignature()
+	for i, priv := range privs {
+		augmented = augmented.Aggregate(priv.SignAugmented(msgs[i]))
+	}
+	require.True(t, bls.AggregateVerifyAugmented(pubs, msgs, augmented))
+	require.False(t, bls.AggregateVerifyAugmented(pubs, msgs, sig))
+	require.False(t, bls.AggregateVerify(pubs, msgs, augmented))
 }
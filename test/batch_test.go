@@ -0,0 +1,82 @@
+package test
+
+import (
+	"crypto/rand"
+	"io"
+	"testing"
+
+	"github.com/eywa-protocol/bls-crypto/bls"
+	"github.com/stretchr/testify/require"
+)
+
+func signatureSets(n int) []bls.SignatureSet {
+	privs, pubs := GenerateRandomKeys(n)
+	sets := make([]bls.SignatureSet, n)
+	for i, priv := range privs {
+		m := GenRandomBytes(MESSAGE_SIZE)
+		sets[i] = bls.SignatureSet{Pub: pubs[i], Msg: m, Sig: priv.Sign(m)}
+	}
+	return sets
+}
+
+func batchInvalid(t *testing.T, sets []bls.SignatureSet) []int {
+	invalid, err := bls.BatchVerify(sets)
+	require.NoError(t, err)
+	return invalid
+}
+
+func Test_BatchVerify(t *testing.T) {
+	sets := signatureSets(16)
+	require.Empty(t, batchInvalid(t, sets))
+	require.Empty(t, batchInvalid(t, sets[:1]))
+	require.Empty(t, batchInvalid(t, nil))
+
+	// The same message signed by different keys
+	sets[1].Msg = sets[0].Msg
+	sets[1].Sig = privs[1].Sign(sets[0].Msg)
+	sets[1].Pub = pubs[1]
+	require.Empty(t, batchInvalid(t, sets))
+}
+
+func Test_BatchVerifyFindsInvalid(t *testing.T) {
+	sets := signatureSets(13)
+	sets[0].Msg = GenRandomBytes(MESSAGE_SIZE)
+	sets[7].Sig = sets[8].Sig
+	sets[12].Pub = pubs[0]
+	require.Equal(t, []int{0, 7, 12}, batchInvalid(t, sets))
+
+	require.Equal(t, []int{0}, batchInvalid(t, sets[:1]))
+}
+
+func Test_BatchVerifySwappedSignatures(t *testing.T) {
+	// Two signatures by the same key swapped between the messages keep the
+	// plain sum valid, but are caught thanks to the random coefficients
+	sets := signatureSets(4)
+	m1, m2 := GenRandomBytes(MESSAGE_SIZE), GenRandomBytes(MESSAGE_SIZE)
+	sets[1] = bls.SignatureSet{Pub: pubs[0], Msg: m1, Sig: privs[0].Sign(m2)}
+	sets[2] = bls.SignatureSet{Pub: pubs[0], Msg: m2, Sig: privs[0].Sign(m1)}
+	require.Equal(t, []int{1, 2}, batchInvalid(t, sets))
+}
+
+func Test_BatchVerifyInfinity(t *testing.T) {
+	// The zero signature of the key at infinity passes the plain pairing check
+	sets := signatureSets(3)
+	sets[1] = bls.SignatureSet{Pub: bls.ZeroPublicKey(), Msg: sets[1].Msg, Sig: bls.ZeroSignature()}
+	require.False(t, sets[1].Sig.Verify(sets[1].Pub, sets[1].Msg))
+	require.Equal(t, []int{1}, batchInvalid(t, sets))
+}
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, io.ErrUnexpectedEOF
+}
+
+func Test_BatchVerifyRandomnessFailure(t *testing.T) {
+	sets := signatureSets(2)
+	reader := rand.Reader
+	rand.Reader = failingReader{}
+	defer func() { rand.Reader = reader }()
+	_, err := bls.BatchVerify(sets)
+	require.Equal(t, io.ErrUnexpectedEOF, err)
+}
@@ -0,0 +1,84 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/eywa-protocol/bls-crypto/bls"
+	"github.com/stretchr/testify/require"
+)
+
+func contributions(signers ...uint32) []bls.Contribution {
+	res := make([]bls.Contribution, len(signers))
+	for i, index := range signers {
+		res[i] = bls.Contribution{Index: index, Signature: privs[index].Multisign(msg, aggPub, mks[index])}
+	}
+	return res
+}
+
+func Test_BlameInvalidContributions(t *testing.T) {
+	contribs := contributions(0, 3, 5, 8, 13, 21, 34, 55, 60, 61, 62, 63)
+	// signed by another membership key, another message and nothing
+	contribs[2].Signature = privs[5].Multisign(msg, aggPub, mks[6])
+	contribs[6].Signature = privs[34].Multisign(GenRandomBytes(MESSAGE_SIZE), aggPub, mks[34])
+	contribs[9].Signature = bls.Signature{}
+
+	aggregated := bls.NewZeroMultisig()
+	for _, contrib := range contribs[:9] {
+		aggregated.PartSignature = aggregated.PartSignature.Aggregate(contrib.Signature)
+		aggregated.PartPublicKey = aggregated.PartPublicKey.Aggregate(pubs[contrib.Index])
+		aggregated.PartMask.SetBit(aggregated.PartMask, int(contrib.Index), 1)
+	}
+	require.False(t, aggregated.Verify(aggPub, msg))
+
+	multi, invalid, err := bls.Blame(aggPub, pubs, msg, contribs)
+	require.NoError(t, err)
+	require.Equal(t, []uint32{5, 34, 61}, invalid)
+	require.True(t, multi.Verify(aggPub, msg))
+
+	mask := new(big.Int)
+	for _, i := range []int{0, 3, 8, 13, 21, 55, 60, 62, 63} {
+		mask.SetBit(mask, i, 1)
+	}
+	require.Equal(t, mask, multi.PartMask)
+}
+
+func Test_BlameValidContributions(t *testing.T) {
+	contribs := contributions(1, 2, 7)
+	multi, invalid, err := bls.Blame(aggPub, pubs, msg, contribs)
+	require.NoError(t, err)
+	require.Empty(t, invalid)
+	require.True(t, multi.Verify(aggPub, msg))
+	require.Equal(t, big.NewInt(0b10000110), multi.PartMask)
+
+	multi, invalid, err = bls.Blame(aggPub, pubs, GenRandomBytes(MESSAGE_SIZE), contribs)
+	require.NoError(t, err)
+	require.Equal(t, []uint32{1, 2, 7}, invalid)
+	require.Equal(t, 0, multi.PartMask.Sign())
+
+	_, _, err = bls.Blame(aggPub, pubs, msg, contributions(1, 1))
+	require.Equal(t, bls.ErrDuplicateSigner, err)
+	_, _, err = bls.Blame(aggPub, pubs[:2], msg, contribs)
+	require.Equal(t, bls.ErrIndexRange, err)
+	_, _, err = bls.Blame(bls.PublicKey{}, pubs, msg, contribs)
+	require.Equal(t, bls.ErrNilKey, err)
+}
+
+func Test_BlameCancellingContributions(t *testing.T) {
+	// S3 + Δ and S8 - Δ add up to the valid S3 + S8
+	contribs := contributions(1, 3, 8, 13)
+	delta := privs[0].Sign(GenRandomBytes(MESSAGE_SIZE))
+	contribs[1].Signature = contribs[1].Signature.Aggregate(delta)
+	contribs[2].Signature = contribs[2].Signature.Aggregate(delta.ScalarMult(new(big.Int).Sub(bls.Order(), big.NewInt(1))))
+
+	aggregated, invalid, err := bls.Blame(aggPub, pubs, msg, contribs[1:3])
+	require.NoError(t, err)
+	require.Equal(t, []uint32{3, 8}, invalid)
+	require.Equal(t, 0, aggregated.PartMask.Sign())
+
+	multi, invalid, err := bls.Blame(aggPub, pubs, msg, contribs)
+	require.NoError(t, err)
+	require.Equal(t, []uint32{3, 8}, invalid)
+	require.True(t, multi.Verify(aggPub, msg))
+	require.Equal(t, big.NewInt(1<<1|1<<13), multi.PartMask)
+}
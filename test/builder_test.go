@@ -0,0 +1,71 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/eywa-protocol/bls-crypto/bls"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MultisigBuilder(t *testing.T) {
+	builder, err := bls.NewMultisigBuilder(aggPub, pubs, msg)
+	require.NoError(t, err)
+	_, err = builder.Build()
+	require.Equal(t, bls.ErrInvalidMask, err)
+
+	for _, i := range []uint32{5, 0, 63, 17} {
+		require.NoError(t, builder.Add(i, privs[i].Multisign(msg, aggPub, mks[i])))
+	}
+	require.Equal(t, 4, builder.Count())
+	multi, err := builder.Build()
+	require.NoError(t, err)
+	require.True(t, multi.Verify(aggPub, msg))
+
+	mask := new(big.Int)
+	for _, i := range []int{0, 5, 17, 63} {
+		mask.SetBit(mask, i, 1)
+	}
+	pub, sig := signMultisigPartially(mask)
+	require.Equal(t, mask, multi.PartMask)
+	require.Equal(t, pub.Marshal(), multi.PartPublicKey.Marshal())
+	require.Equal(t, sig.Marshal(), multi.PartSignature.Marshal())
+
+	// The built multisignature is not affected by later additions
+	require.NoError(t, builder.Add(1, privs[1].Multisign(msg, aggPub, mks[1])))
+	require.Equal(t, mask, multi.PartMask)
+	require.True(t, multi.Verify(aggPub, msg))
+}
+
+func Test_MultisigBuilderRejects(t *testing.T) {
+	builder, err := bls.NewMultisigBuilder(aggPub, pubs, msg)
+	require.NoError(t, err)
+
+	sig := privs[3].Multisign(msg, aggPub, mks[3])
+	require.True(t, sig.VerifyMultisignPart(aggPub, pubs[3], 3, msg))
+	require.NoError(t, builder.Add(3, sig))
+	require.Equal(t, bls.ErrDuplicateSigner, builder.Add(3, sig))
+	require.Equal(t, bls.ErrIndexRange, builder.Add(uint32(len(pubs)), sig))
+	require.Equal(t, bls.ErrNilSignature, builder.Add(4, bls.Signature{}))
+
+	// Signed by another member, another membership key or another message
+	require.Equal(t, bls.ErrInvalidSignature, builder.Add(4, sig))
+	require.Equal(t, bls.ErrInvalidSignature, builder.Add(4, privs[4].Multisign(msg, aggPub, mks[5])))
+	require.Equal(t, bls.ErrInvalidSignature, builder.Add(4, privs[4].Multisign(GenRandomBytes(MESSAGE_SIZE), aggPub, mks[4])))
+	require.Equal(t, 1, builder.Count())
+
+	_, err = bls.NewMultisigBuilder(bls.PublicKey{}, pubs, msg)
+	require.Equal(t, bls.ErrNilKey, err)
+	_, err = bls.NewMultisigBuilder(aggPub, nil, msg)
+	require.Equal(t, bls.ErrNoKeys, err)
+	_, err = bls.NewMultisigBuilder(aggPub, []bls.PublicKey{pubs[0], bls.ZeroPublicKey()}, msg)
+	require.Equal(t, bls.ErrInfinity, err)
+
+	// The aggregated public key must be the one of the members
+	_, err = bls.NewMultisigBuilder(aggPub, pubs[:len(pubs)-1], msg)
+	require.Equal(t, bls.ErrAggregateMismatch, err)
+	_, err = bls.NewMultisigBuilder(pubs[0].Aggregate(pubs[1]), pubs[:2], msg)
+	require.Equal(t, bls.ErrAggregateMismatch, err)
+	_, err = bls.NewMultisigBuilder(bls.AggregatePublicKeys(pubs[:2], bls.CalculateAntiRogueCoefficients(pubs[:2])), pubs[:2], msg)
+	require.NoError(t, err)
+}
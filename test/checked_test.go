@@ -0,0 +1,108 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/eywa-protocol/bls-crypto/bls"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CheckedSignAndVerify(t *testing.T) {
+	priv, pub := bls.GenerateRandomKey()
+	sig, err := priv.SignChecked(msg)
+	require.NoError(t, err)
+	require.Equal(t, priv.Sign(msg).Marshal(), sig.Marshal())
+	require.NoError(t, sig.VerifyChecked(pub, msg))
+	require.Equal(t, bls.ErrInvalidSignature, sig.VerifyChecked(pubs[0], msg))
+
+	_, err = bls.PrivateKey{}.SignChecked(msg)
+	require.Equal(t, bls.ErrNilKey, err)
+	zeroPriv, err := bls.UnmarshalPrivateKey([]byte("0"))
+	require.NoError(t, err)
+	_, err = zeroPriv.SignChecked(msg)
+	require.Equal(t, bls.ErrScalarRange, err)
+
+	nilPub, err := bls.UnmarshalPublicKey(nil)
+	require.NoError(t, err)
+	nilSig, err := bls.UnmarshalSignature(nil)
+	require.NoError(t, err)
+	require.Equal(t, bls.ErrNilKey, sig.VerifyChecked(nilPub, msg))
+	require.Equal(t, bls.ErrNilSignature, nilSig.VerifyChecked(pub, msg))
+	require.Equal(t, bls.ErrInfinity, bls.ZeroSignature().VerifyChecked(bls.ZeroPublicKey(), msg))
+
+	// The unchecked API does not panic either
+	require.False(t, sig.Verify(nilPub, msg))
+	require.False(t, nilSig.Verify(pub, msg))
+	require.False(t, bls.ZeroSignature().Verify(bls.ZeroPublicKey(), msg))
+	require.False(t, bls.ZeroSignature().VerifyWith(bls.BasicCiphersuite, bls.ZeroPublicKey(), msg))
+	require.False(t, nilPub.VerifyPossession(sig))
+	require.False(t, bls.FastAggregateVerify([]bls.PublicKey{pub, nilPub}, msg, sig))
+	require.False(t, bls.AggregateVerify([]bls.PublicKey{nilPub}, [][]byte{msg}, sig))
+	require.False(t, bls.AggregateVerifyAugmented([]bls.PublicKey{nilPub}, [][]byte{msg}, sig))
+	require.False(t, nilSig.VerifyMembershipKeyPart(aggPub, pub, *big.NewInt(1), 0))
+}
+
+func Test_CheckedMultisig(t *testing.T) {
+	mask := big.NewInt(0b1011)
+	pub, sig := signMultisigPartially(mask)
+	multi := bls.Multisig{PartSignature: sig, PartPublicKey: pub, PartMask: mask}
+	require.NoError(t, multi.VerifyChecked(aggPub, msg))
+	require.Equal(t, bls.ErrInvalidSignature, multi.VerifyChecked(aggPub, GenRandomBytes(MESSAGE_SIZE)))
+
+	_, err := privs[0].MultisignChecked(msg, aggPub, bls.Signature{})
+	require.Equal(t, bls.ErrNilSignature, err)
+	_, err = privs[0].MultisignChecked(msg, bls.PublicKey{}, mks[0])
+	require.Equal(t, bls.ErrNilKey, err)
+
+	invalid := []struct {
+		multi bls.Multisig
+		err   error
+	}{
+		{bls.Multisig{PartSignature: sig, PartPublicKey: pub}, bls.ErrInvalidMask},
+		{bls.Multisig{PartSignature: sig, PartPublicKey: pub, PartMask: big.NewInt(-1)}, bls.ErrInvalidMask},
+		{bls.Multisig{PartPublicKey: pub, PartMask: mask}, bls.ErrNilSignature},
+		{bls.Multisig{PartSignature: sig, PartMask: mask}, bls.ErrNilKey},
+	}
+	for i, test := range invalid {
+		require.Equal(t, test.err, test.multi.VerifyChecked(aggPub, msg), "case #%d", i)
+		require.False(t, test.multi.Verify(aggPub, msg), "case #%d", i)
+	}
+	require.False(t, multi.Verify(bls.PublicKey{}, msg))
+
+	// Nobody signed: the unchecked check passes vacuously
+	require.True(t, bls.NewZeroMultisig().Verify(aggPub, msg))
+	require.Equal(t, bls.ErrInvalidMask, bls.NewZeroMultisig().VerifyChecked(aggPub, msg))
+}
+
+func Test_CheckedAggregation(t *testing.T) {
+	coefs, err := bls.CalculateAntiRogueCoefficientsChecked(pubs)
+	require.NoError(t, err)
+	require.Equal(t, as, coefs)
+	res, err := bls.AggregatePublicKeysChecked(pubs, as)
+	require.NoError(t, err)
+	require.Equal(t, aggPub.Marshal(), res.Marshal())
+
+	_, err = bls.CalculateAntiRogueCoefficientsChecked(nil)
+	require.Equal(t, bls.ErrNoKeys, err)
+	_, err = bls.CalculateAntiRogueCoefficientsChecked([]bls.PublicKey{pubs[0], {}})
+	require.Equal(t, bls.ErrNilKey, err)
+	_, err = bls.AggregatePublicKeysChecked(pubs, as[1:])
+	require.Equal(t, bls.ErrLengthMismatch, err)
+	_, err = bls.AggregatePublicKeysChecked([]bls.PublicKey{bls.ZeroPublicKey()}, as[:1])
+	require.Equal(t, bls.ErrInfinity, err)
+	_, err = bls.AggregateSignaturesChecked([]bls.Signature{{}}, as[:1])
+	require.Equal(t, bls.ErrNilSignature, err)
+	_, err = pubs[0].AggregateChecked(bls.PublicKey{})
+	require.Equal(t, bls.ErrNilKey, err)
+	_, err = bls.ZeroSignature().AggregateChecked(bls.Signature{})
+	require.Equal(t, bls.ErrNilSignature, err)
+}
+
+func Test_BatchVerifyNilSets(t *testing.T) {
+	sets := signatureSets(5)
+	sets[1].Sig = bls.Signature{}
+	sets[3].Pub = bls.PublicKey{}
+	sets[4].Msg = GenRandomBytes(MESSAGE_SIZE)
+	require.Equal(t, []int{1, 3, 4}, batchInvalid(t, sets))
+}
@@ -0,0 +1,154 @@
+package test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+	"github.com/eywa-protocol/bls-crypto/bls"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_CompressPublicKey(t *testing.T) {
+	flags := make(map[byte]bool)
+	for _, pub := range pubs {
+		raw := pub.MarshalCompressed()
+		require.Len(t, raw, bls.CompressedPublicKeySize)
+		flags[raw[0]>>6] = true
+		decoded, err := bls.UnmarshalCompressedPublicKey(raw)
+		require.NoError(t, err)
+		require.Equal(t, pub.Marshal(), decoded.Marshal())
+	}
+	// Both signs of y occur
+	require.Equal(t, map[byte]bool{0b10: true, 0b11: true}, flags)
+
+	infinity := publicKey.ScalarMult(big.NewInt(0))
+	raw := infinity.MarshalCompressed()
+	require.Equal(t, append([]byte{0x40}, make([]byte, 63)...), raw)
+	decoded, err := bls.UnmarshalCompressedPublicKey(raw)
+	require.NoError(t, err)
+	require.Equal(t, infinity.Marshal(), decoded.Marshal())
+
+	require.Nil(t, bls.PublicKey{}.MarshalCompressed())
+}
+
+func Test_CompressSignature(t *testing.T) {
+	flags := make(map[byte]bool)
+	for _, priv := range privs {
+		sig := priv.Sign(msg)
+		raw := sig.MarshalCompressed()
+		require.Len(t, raw, bls.CompressedSignatureSize)
+		flags[raw[0]>>6] = true
+		decoded, err := bls.UnmarshalCompressedSignature(raw)
+		require.NoError(t, err)
+		require.Equal(t, sig.Marshal(), decoded.Marshal())
+		require.True(t, decoded.Verify(priv.PublicKey(), msg))
+	}
+	require.Equal(t, map[byte]bool{0b10: true, 0b11: true}, flags)
+
+	raw := bls.ZeroSignature().MarshalCompressed()
+	require.Equal(t, append([]byte{0x40}, make([]byte, 31)...), raw)
+	decoded, err := bls.UnmarshalCompressedSignature(raw)
+	require.NoError(t, err)
+	require.Equal(t, bls.ZeroSignature().Marshal(), decoded.Marshal())
+
+	require.Nil(t, bls.Signature{}.MarshalCompressed())
+}
+
+func Test_CompressMalformed(t *testing.T) {
+	pub := publicKey.MarshalCompressed()
+	sig := secretKey.Sign(msg).MarshalCompressed()
+	withFlags := func(raw []byte, flags byte) []byte {
+		res := append([]byte{}, raw...)
+		res[0] = res[0]&0x3f | flags
+		return res
+	}
+	p := make([]byte, 32)
+	bn256.P.FillBytes(p)
+
+	pubCases := []struct {
+		raw []byte
+		err error
+	}{
+		{nil, bls.ErrInvalidLength},
+		{pub[:63], bls.ErrInvalidLength},
+		{publicKey.Marshal(), bls.ErrInvalidLength},
+		{withFlags(pub, 0), bls.ErrNonCanonical},
+		{withFlags(pub, 0x40), bls.ErrNonCanonical},
+		{withWord(pub, 1, p), bls.ErrNonCanonical},
+		{withFlags(withWord(pub, 0, p), 0x80), bls.ErrNonCanonical},
+		// x of a twist point out of G2
+		{withFlags(mustDecodeHex(t, twistPointOutOfG2)[:64], 0x80), bls.ErrNotInSubgroup},
+		{withFlags(mustDecodeHex(t, twistPointOutOfG2)[:64], 0xc0), bls.ErrNotInSubgroup},
+	}
+	for i, c := range pubCases {
+		_, err := bls.UnmarshalCompressedPublicKey(c.raw)
+		require.Equal(t, c.err, err, "case %d", i)
+	}
+
+	sigCases := []struct {
+		raw []byte
+		err error
+	}{
+		{nil, bls.ErrInvalidLength},
+		{sig[:31], bls.ErrInvalidLength},
+		{secretKey.Sign(msg).Marshal(), bls.ErrInvalidLength},
+		{withFlags(sig, 0), bls.ErrNonCanonical},
+		{withFlags(sig, 0x40), bls.ErrNonCanonical},
+		{withFlags(p, 0x80), bls.ErrNonCanonical},
+	}
+	for i, c := range sigCases {
+		_, err := bls.UnmarshalCompressedSignature(c.raw)
+		require.Equal(t, c.err, err, "case %d", i)
+	}
+
+	// x with no point on the curve: x³ + 3 is not a square
+	x := big.NewInt(1)
+	for ; ; x.Add(x, big.NewInt(1)) {
+		rhs := new(big.Int).Exp(x, big.NewInt(3), nil)
+		if big.Jacobi(rhs.Add(rhs, big.NewInt(3)), bn256.P) == -1 {
+			break
+		}
+	}
+	_, err := bls.UnmarshalCompressedSignature(withFlags(withWord(sig, 0, x.Bytes()), 0x80))
+	require.Equal(t, bls.ErrNotOnCurve, err)
+
+	// About half of the x coordinates have no point on the twist
+	notOnCurve := 0
+	for k := int64(1); k <= 20; k++ {
+		raw := withFlags(withWord(make([]byte, 64), 1, big.NewInt(k).Bytes()), 0x80)
+		if _, err := bls.UnmarshalCompressedPublicKey(raw); err == bls.ErrNotOnCurve {
+			notOnCurve++
+		} else {
+			require.Equal(t, bls.ErrNotInSubgroup, err)
+		}
+	}
+	require.NotZero(t, notOnCurve)
+}
+
+func Test_ReadCompressed(t *testing.T) {
+	pub, err := bls.ReadPublicKey(hex.EncodeToString(publicKey.MarshalCompressed()))
+	require.NoError(t, err)
+	require.Equal(t, publicKey.Marshal(), pub.Marshal())
+
+	signature := secretKey.Sign(msg)
+	sig, err := bls.ReadSignature(hex.EncodeToString(signature.MarshalCompressed()))
+	require.NoError(t, err)
+	require.Equal(t, signature.Marshal(), sig.Marshal())
+
+	// Uncompressed points are still read
+	pub, err = bls.ReadPublicKey(hex.EncodeToString(publicKey.Marshal()))
+	require.NoError(t, err)
+	require.Equal(t, publicKey.Marshal(), pub.Marshal())
+
+	_, err = bls.ReadPublicKey(hex.EncodeToString(make([]byte, bls.CompressedPublicKeySize)))
+	require.Equal(t, bls.ErrNonCanonical, err)
+
+	// JSON accepts compressed points too
+	data, err := json.Marshal(hex.EncodeToString(publicKey.MarshalCompressed()))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &pub))
+	require.Equal(t, publicKey.Marshal(), pub.Marshal())
+}
@@ -0,0 +1,82 @@
+package test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/eywa-protocol/bls-crypto/bls"
+	"github.com/stretchr/testify/require"
+)
+
+// derivationSeed is the seed of the first EIP-2333 test case. The derived
+// keys differ from the EIP ones since they are reduced modulo the bn256
+// group order.
+const derivationSeed = "c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04"
+
+func requireKeyEqual(t *testing.T, expected string, actual bls.PrivateKey) {
+	priv, err := bls.UnmarshalPrivateKey([]byte(expected))
+	require.NoError(t, err)
+	require.Equal(t, priv.Marshal(), actual.Marshal())
+}
+
+func Test_DeriveKeyVectors(t *testing.T) {
+	seed, err := hex.DecodeString(derivationSeed)
+	require.NoError(t, err)
+
+	master, err := bls.DeriveMasterKey(seed)
+	require.NoError(t, err)
+	requireKeyEqual(t, "16876385784863514523309488032647671531381760176997820269052892961094459323096", master)
+	child, err := master.DeriveChild(0)
+	require.NoError(t, err)
+	requireKeyEqual(t, "6261163673700163178650738809658100478163222593983987165305930523660281595207", child)
+
+	priv, err := bls.DeriveKey(seed, "m/12381/60/0/0")
+	require.NoError(t, err)
+	requireKeyEqual(t, "9858384286953664294113346328707988549089656255211929188011147946764474537278", priv)
+	require.Equal(t, "06e6555792713c01bab3c493b9a111905b7ec9ebf73f099548f433e735880fe911b0bd1de4a3272d9ef78a9ca13cef9040c6bab82c0ad8f38d1b0d19d1d07fe6041e8bbde5b2cc5ef0fcda6b5330de59a43a0c3809f47504cde77fa2ac09b428288d520d2c506a8a28106c1e3bee5a36d7414bfe3467550d9e42712e866bae68",
+		hex.EncodeToString(priv.PublicKey().Marshal()))
+}
+
+func Test_DeriveKeyPath(t *testing.T) {
+	seed := GenRandomBytes(32)
+	master, err := bls.DeriveMasterKey(seed)
+	require.NoError(t, err)
+
+	priv, err := bls.DeriveKey(seed, "m/12381/60/1/0")
+	require.NoError(t, err)
+	child := master
+	for _, index := range []uint32{12381, 60, 1, 0} {
+		child, err = child.DeriveChild(index)
+		require.NoError(t, err)
+	}
+	require.Equal(t, child.Marshal(), priv.Marshal())
+
+	other, err := bls.DeriveKey(seed, "m/12381/60/2/0")
+	require.NoError(t, err)
+	require.NotEqual(t, priv.Marshal(), other.Marshal())
+
+	root, err := bls.DeriveKey(seed, "m")
+	require.NoError(t, err)
+	require.Equal(t, master.Marshal(), root.Marshal())
+
+	// Derived keys are usable for signing
+	sig := priv.Sign(msg)
+	require.True(t, sig.Verify(priv.PublicKey(), msg))
+}
+
+func Test_DeriveKeyErrors(t *testing.T) {
+	_, err := bls.DeriveMasterKey(GenRandomBytes(31))
+	require.Error(t, err)
+
+	seed := GenRandomBytes(32)
+	for _, path := range []string{"", "12381/60", "m/", "m/12381/-1", "m/4294967296", "m/60'"} {
+		_, err = bls.DeriveKey(seed, path)
+		require.Error(t, err, path)
+	}
+	indices, err := bls.ParseDerivationPath("m/12381/60/4294967295/0")
+	require.NoError(t, err)
+	require.Equal(t, []uint32{12381, 60, 4294967295, 0}, indices)
+
+	_, err = bls.PrivateKey{}.DeriveChild(0)
+	require.Equal(t, bls.ErrNilKey, err)
+}
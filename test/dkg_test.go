@@ -0,0 +1,269 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/eywa-protocol/bls-crypto/bls"
+	"github.com/eywa-protocol/bls-crypto/dkg"
+	"github.com/eywa-protocol/bls-crypto/threshold"
+	"github.com/stretchr/testify/require"
+)
+
+func newDKGNodes(t *testing.T, total, thresh int) []*dkg.Node {
+	participants := make([]uint32, total)
+	for i := range participants {
+		participants[i] = uint32(i + 1)
+	}
+	nodes := make([]*dkg.Node, total)
+	for i := range nodes {
+		node, err := dkg.NewNode(dkg.Config{Index: participants[i], Participants: participants, Threshold: thresh})
+		require.NoError(t, err)
+		nodes[i] = node
+	}
+	return nodes
+}
+
+// runDKG runs all phases of the key generation between the nodes, the
+// results of the nodes getting no share are nil. The broadcasts, deals
+// and justifications are passed through the filters simulating malicious
+// dealers, which may alter the message or drop it by returning false.
+func runDKG(t *testing.T, nodes []*dkg.Node, broadcastFilter func(*dkg.Broadcast) bool, dealFilter func(*dkg.Deal) bool, justificationFilter func(*dkg.Justification) bool) []*dkg.Result {
+	byIndex := make(map[uint32]*dkg.Node, len(nodes))
+	for _, node := range nodes {
+		byIndex[node.Index()] = node
+	}
+	for _, dealer := range nodes {
+		broadcast := dealer.Broadcast()
+		if broadcast == nil || (broadcastFilter != nil && !broadcastFilter(broadcast)) {
+			continue
+		}
+		for _, node := range nodes {
+			if node != dealer {
+				require.NoError(t, node.ProcessBroadcast(*broadcast))
+			}
+		}
+	}
+	for _, dealer := range nodes {
+		deals, err := dealer.Deals()
+		require.NoError(t, err)
+		for _, deal := range deals {
+			if dealFilter != nil && !dealFilter(&deal) {
+				continue
+			}
+			require.NoError(t, byIndex[deal.Recipient].ProcessDeal(deal))
+		}
+	}
+
+	var complaints []dkg.Complaint
+	for _, node := range nodes {
+		complaints = append(complaints, node.Complaints()...)
+	}
+	var justifications []dkg.Justification
+	for _, complaint := range complaints {
+		for _, node := range nodes {
+			justification, err := node.ProcessComplaint(complaint)
+			require.NoError(t, err)
+			if justification != nil {
+				require.Equal(t, node.Index(), complaint.Dealer)
+				if justificationFilter == nil || justificationFilter(justification) {
+					justifications = append(justifications, *justification)
+				}
+			}
+		}
+	}
+	for _, justification := range justifications {
+		for _, node := range nodes {
+			require.NoError(t, node.ProcessJustification(justification))
+		}
+	}
+
+	results := make([]*dkg.Result, len(nodes))
+	for i, node := range nodes {
+		result, err := node.Finalize()
+		if err == dkg.ErrNotRecipient {
+			continue
+		}
+		require.NoError(t, err)
+		results[i] = result
+	}
+	return results
+}
+
+// requireAgreement checks that all the nodes agree on the qualified set and
+// the group key, and that their shares produce threshold signatures
+func requireAgreement(t *testing.T, results []*dkg.Result, qualified []uint32) {
+	pub := results[0].PublicKey()
+	for _, result := range results {
+		require.Equal(t, qualified, result.Qualified)
+		require.Equal(t, pub.Marshal(), result.PublicKey().Marshal())
+		require.True(t, result.Commitments.VerifyShare(result.Share))
+	}
+
+	thresh := results[0].Commitments.Threshold()
+	sigs := make([]bls.Signature, thresh)
+	indices := make([]uint32, thresh)
+	for i, result := range results[len(results)-thresh:] {
+		sigs[i] = threshold.PartialSign(result.Share, msg)
+		indices[i] = result.Share.Index
+	}
+	sig, err := threshold.CombineSignatures(sigs, indices)
+	require.NoError(t, err)
+	require.True(t, sig.Verify(pub, msg))
+}
+
+func Test_DKGHonest(t *testing.T) {
+	results := runDKG(t, newDKGNodes(t, 5, 3), nil, nil, nil)
+	requireAgreement(t, results, []uint32{1, 2, 3, 4, 5})
+}
+
+func Test_DKGJustifiedComplaints(t *testing.T) {
+	// #2 sends an invalid share to #4 and #3 sends nothing to #1, but both
+	// justify the complaints
+	corrupt := func(deal *dkg.Deal) bool {
+		if deal.Dealer == 2 && deal.Recipient == 4 {
+			deal.Share, _ = bls.GenerateRandomKey()
+		}
+		return deal.Dealer != 3 || deal.Recipient != 1
+	}
+	results := runDKG(t, newDKGNodes(t, 5, 3), nil, corrupt, nil)
+	requireAgreement(t, results, []uint32{1, 2, 3, 4, 5})
+}
+
+func Test_DKGDisqualifiesDealers(t *testing.T) {
+	invalid := func(broadcast *dkg.Broadcast) bool {
+		if broadcast.Dealer == 6 {
+			// invalid commitments
+			broadcast.Commitments = broadcast.Commitments[1:]
+		}
+		return true
+	}
+	corrupt := func(deal *dkg.Deal) bool {
+		switch {
+		case deal.Dealer == 2 && deal.Recipient == 4:
+			// invalid share, no justification
+			deal.Share, _ = bls.GenerateRandomKey()
+		case deal.Dealer == 5 && deal.Recipient == 1:
+			// invalid share, invalid justification
+			deal.Share, _ = bls.GenerateRandomKey()
+		}
+		return true
+	}
+	withhold := func(justification *dkg.Justification) bool {
+		if justification.Dealer == 5 {
+			justification.Share, _ = bls.GenerateRandomKey()
+		}
+		return justification.Dealer != 2
+	}
+	results := runDKG(t, newDKGNodes(t, 7, 3), invalid, corrupt, withhold)
+	// The malicious dealers may see themselves qualified
+	honest := []*dkg.Result{results[0], results[2], results[3], results[6]}
+	requireAgreement(t, honest, []uint32{1, 3, 4, 7})
+}
+
+func Test_DKGDisqualifiesInvalidPoints(t *testing.T) {
+	invalid := func(broadcast *dkg.Broadcast) bool {
+		switch broadcast.Dealer {
+		case 2:
+			// the zero point
+			broadcast.Commitments[1] = bls.ZeroPublicKey()
+		case 3:
+			// the nil point
+			broadcast.Commitments[2] = bls.PublicKey{}
+		case 4:
+			// no broadcast at all
+			return false
+		}
+		return true
+	}
+	results := runDKG(t, newDKGNodes(t, 6, 3), invalid, nil, nil)
+	honest := []*dkg.Result{results[0], results[4], results[5]}
+	requireAgreement(t, honest, []uint32{1, 5, 6})
+}
+
+func Test_DKGInconsistentDeals(t *testing.T) {
+	// #2 deals the shares of another polynomial to #3 and #4, as if it had
+	// sent them other commitments, and justifies by them
+	other, _ := bls.GenerateRandomKey()
+	otherPoly, err := threshold.NewPolynomial(other, 3)
+	require.NoError(t, err)
+	otherShare := func(recipient uint32) bls.PrivateKey {
+		share, err := otherPoly.Share(recipient)
+		require.NoError(t, err)
+		return share.Key
+	}
+	corrupt := func(deal *dkg.Deal) bool {
+		if deal.Dealer == 2 && (deal.Recipient == 3 || deal.Recipient == 4) {
+			deal.Share = otherShare(deal.Recipient)
+		}
+		return true
+	}
+	justify := func(justification *dkg.Justification) bool {
+		if justification.Dealer == 2 {
+			justification.Share = otherShare(justification.Complainer)
+		}
+		return true
+	}
+	results := runDKG(t, newDKGNodes(t, 5, 3), nil, corrupt, justify)
+	honest := []*dkg.Result{results[0], results[2], results[3], results[4]}
+	requireAgreement(t, honest, []uint32{1, 3, 4, 5})
+
+	// The deal may come before the broadcast
+	nodes := newDKGNodes(t, 3, 2)
+	deals, err := nodes[1].Deals()
+	require.NoError(t, err)
+	require.NoError(t, nodes[0].ProcessDeal(deals[0]))
+	require.Contains(t, nodes[0].Complaints(), dkg.Complaint{Complainer: 1, Dealer: 2})
+	require.NoError(t, nodes[0].ProcessBroadcast(*nodes[1].Broadcast()))
+	require.NotContains(t, nodes[0].Complaints(), dkg.Complaint{Complainer: 1, Dealer: 2})
+	require.Equal(t, dkg.ErrDuplicateBroadcast, nodes[0].ProcessBroadcast(*nodes[1].Broadcast()))
+}
+
+func Test_DKGNotEnoughQualified(t *testing.T) {
+	nodes := newDKGNodes(t, 3, 3)
+	for _, dealer := range nodes[1:] {
+		for _, node := range nodes {
+			if node != dealer {
+				require.NoError(t, node.ProcessBroadcast(*dealer.Broadcast()))
+			}
+		}
+		deals, err := dealer.Deals()
+		require.NoError(t, err)
+		for _, deal := range deals {
+			require.NoError(t, nodes[deal.Recipient-1].ProcessDeal(deal))
+		}
+	}
+	require.Equal(t, []dkg.Complaint{{Complainer: 2, Dealer: 1}}, nodes[1].Complaints())
+	_, err := nodes[1].ProcessComplaint(dkg.Complaint{Complainer: 2, Dealer: 1})
+	require.NoError(t, err)
+	_, err = nodes[1].Finalize()
+	require.Equal(t, dkg.ErrNotEnoughQualified, err)
+}
+
+func Test_DKGErrors(t *testing.T) {
+	for _, config := range []dkg.Config{
+		{Index: 1, Participants: []uint32{1, 2, 3}, Threshold: 0},
+		{Index: 1, Participants: []uint32{1, 2, 3}, Threshold: 4},
+		{Index: 4, Participants: []uint32{1, 2, 3}, Threshold: 2},
+		{Index: 1, Participants: []uint32{1, 2, 2}, Threshold: 2},
+		{Index: 0, Participants: []uint32{0, 1, 2}, Threshold: 2},
+	} {
+		_, err := dkg.NewNode(config)
+		require.Equal(t, dkg.ErrInvalidConfig, err)
+	}
+
+	nodes := newDKGNodes(t, 3, 2)
+	deals, err := nodes[0].Deals()
+	require.NoError(t, err)
+	require.Equal(t, dkg.ErrWrongRecipient, nodes[2].ProcessDeal(deals[0]))
+	require.NoError(t, nodes[1].ProcessDeal(deals[0]))
+	require.Equal(t, dkg.ErrDuplicateDeal, nodes[1].ProcessDeal(deals[0]))
+	deals[0].Dealer = 9
+	require.Equal(t, dkg.ErrUnknownParticipant, nodes[1].ProcessDeal(deals[0]))
+	require.Equal(t, dkg.ErrUnknownParticipant, nodes[1].ProcessBroadcast(dkg.Broadcast{Dealer: 9}))
+
+	_, err = nodes[1].ProcessComplaint(dkg.Complaint{Complainer: 2, Dealer: 2})
+	require.Equal(t, dkg.ErrInvalidComplaint, err)
+	_, err = nodes[1].ProcessComplaint(dkg.Complaint{Complainer: 2, Dealer: 9})
+	require.Equal(t, dkg.ErrUnknownParticipant, err)
+	require.Equal(t, dkg.ErrUnexpectedJustification, nodes[1].ProcessJustification(dkg.Justification{Dealer: 1, Complainer: 3}))
+}
@@ -1,11 +1,16 @@
 package test
 
 import (
+	"testing"
+
 	"github.com/eywa-protocol/bls-crypto/bls"
 	"github.com/stretchr/testify/require"
-	"testing"
 )
 
+// legacyEncryptedKey is the key 194cd886...346b5 encrypted with "password" by
+// the MD5-based scheme used before the scrypt envelope
+const legacyEncryptedKey = "7bb39d3ff1cf026a0922f156463622ec46d76a7ebfdf287de93a7ec00db592f0ee34273836e2eb22ef300667c4d4d559eb6d24935d979e21c4c041c6864b2d76f85870b8b9e9dd4f85d36bbd463a354aa674ca641b55c3c4c80124f517bd12088d3400510e8497d3f7"
+
 func Test_EncryptDecrypt(t *testing.T) {
 	pass := "password"
 	priv0, _ := bls.GenerateRandomKey()
@@ -19,3 +24,94 @@ func Test_EncryptDecrypt(t *testing.T) {
 	require.Equal(t, decr, priv0.Marshal())
 
 }
+
+func Test_EncryptWrongPassphrase(t *testing.T) {
+	priv, _ := bls.GenerateRandomKey()
+
+	protectedKey, err := priv.EncryptWithParams("password", bls.LightScryptParams)
+	require.NoError(t, err)
+
+	_, err = bls.Decrypt([]byte(protectedKey), "passw0rd")
+	require.Error(t, err)
+}
+
+func Test_EncryptSaltedEnvelope(t *testing.T) {
+	priv, _ := bls.GenerateRandomKey()
+
+	key1, err := priv.EncryptWithParams("password", bls.LightScryptParams)
+	require.NoError(t, err)
+	key2, err := priv.EncryptWithParams("password", bls.LightScryptParams)
+	require.NoError(t, err)
+	require.NotEqual(t, key1, key2)
+	require.Equal(t, "010c", key1[:4])
+
+	// Tampering with the KDF parameters in the header is detected
+	tampered := []byte(key1)
+	copy(tampered[2:4], "0b")
+	_, err = bls.Decrypt(tampered, "password")
+	require.Error(t, err)
+}
+
+func Test_DecryptLegacy(t *testing.T) {
+	priv, err := bls.ReadPrivateKey("194cd886f74a0a5a064d24855dea732bf1474954b61ecb0ee55b4fb58b7346b5")
+	require.NoError(t, err)
+
+	decr, err := bls.Decrypt([]byte(legacyEncryptedKey), "password")
+	require.NoError(t, err)
+	restored, err := bls.UnmarshalPrivateKey(decr)
+	require.NoError(t, err)
+	require.Equal(t, priv.Marshal(), restored.Marshal())
+
+	_, err = bls.Decrypt([]byte(legacyEncryptedKey), "passw0rd")
+	require.Error(t, err)
+}
+
+func Test_ReencryptLegacy(t *testing.T) {
+	reencrypted, err := bls.Reencrypt([]byte(legacyEncryptedKey), "password", bls.LightScryptParams)
+	require.NoError(t, err)
+	require.NotEqual(t, legacyEncryptedKey, reencrypted)
+
+	legacy, err := bls.Decrypt([]byte(legacyEncryptedKey), "password")
+	require.NoError(t, err)
+	decr, err := bls.Decrypt([]byte(reencrypted), "password")
+	require.NoError(t, err)
+	require.Equal(t, legacy, decr)
+
+	_, err = bls.Reencrypt([]byte(legacyEncryptedKey), "passw0rd", bls.LightScryptParams)
+	require.Error(t, err)
+}
+
+func Test_DecryptMalformed(t *testing.T) {
+	_, err := bls.Decrypt([]byte("01"), "password")
+	require.Error(t, err)
+	_, err = bls.Decrypt(nil, "password")
+	require.Error(t, err)
+}
+
+func Test_DecryptOversizedParams(t *testing.T) {
+	priv, _ := bls.GenerateRandomKey()
+	_, err := priv.EncryptWithParams("password", bls.ScryptParams{LogN: 21, R: 8, P: 1})
+	require.Equal(t, bls.ErrScryptParams, err)
+
+	protectedKey, err := priv.EncryptWithParams("password", bls.LightScryptParams)
+	require.NoError(t, err)
+	for _, header := range []string{
+		"011f", // N = 2^31
+		"0115", // N = 2^21
+		"0100", // N = 1
+	} {
+		crafted := []byte(header + protectedKey[4:])
+		_, err = bls.Decrypt(crafted, "password")
+		require.Equal(t, bls.ErrScryptParams, err, header)
+	}
+	for _, rp := range []string{
+		"ffffffff", // r = p = 65535
+		"00400002", // r⋅p = 128
+		"00200001", // 128⋅r⋅N = 2GB for N = 2^19
+		"00000001", // r = 0
+	} {
+		crafted := []byte(protectedKey[:2] + "13" + rp + protectedKey[12:])
+		_, err = bls.Decrypt(crafted, "password")
+		require.Equal(t, bls.ErrScryptParams, err, rp)
+	}
+}
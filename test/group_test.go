@@ -0,0 +1,162 @@
+package test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/eywa-protocol/bls-crypto/bls"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Group(t *testing.T) {
+	group, err := bls.NewGroup(pubs)
+	require.NoError(t, err)
+	require.Equal(t, len(pubs), group.Size())
+	require.Equal(t, aggPub.Marshal(), group.AggregatedPublicKey().Marshal())
+	require.Equal(t, as, group.AntiRogueCoefficients())
+	require.Equal(t, bls.LegacyMultisigSuite, group.Suite())
+	require.Equal(t, bls.SeparatedMultisigSuite, group.WithSuite(bls.SeparatedMultisigSuite).Suite())
+	require.Equal(t, bls.LegacyMultisigSuite, group.Suite())
+
+	same, err := bls.NewGroup(append([]bls.PublicKey{}, pubs...))
+	require.NoError(t, err)
+	require.Equal(t, group.ID(), same.ID())
+	other, err := bls.NewGroup([]bls.PublicKey{pubs[1], pubs[0]})
+	require.NoError(t, err)
+	require.NotEqual(t, group.ID(), other.ID())
+
+	for _, i := range []uint32{0, 17, uint32(len(pubs) - 1)} {
+		index, ok := group.IndexOf(pubs[i])
+		require.True(t, ok)
+		require.Equal(t, i, index)
+		pub, err := group.PublicKey(i)
+		require.NoError(t, err)
+		require.Equal(t, pubs[i].Marshal(), pub.Marshal())
+	}
+	_, outsider := bls.GenerateRandomKey()
+	_, ok := group.IndexOf(outsider)
+	require.False(t, ok)
+	_, err = group.PublicKey(uint32(len(pubs)))
+	require.Equal(t, bls.ErrIndexRange, err)
+
+	_, err = bls.NewGroup(nil)
+	require.Equal(t, bls.ErrNoKeys, err)
+	_, err = bls.NewGroup([]bls.PublicKey{pubs[0], pubs[1], pubs[0]})
+	require.Equal(t, bls.ErrDuplicateMember, err)
+}
+
+func Test_GroupMembershipKeys(t *testing.T) {
+	const total = 4
+	privs, pubs := GenerateRandomKeys(total)
+	group, err := bls.NewGroup(pubs)
+	require.NoError(t, err)
+
+	expected := AggregateMembershipKeys(privs, pubs, group.AggregatedPublicKey(), group.AntiRogueCoefficients())
+	for recipient := uint32(0); recipient < total; recipient++ {
+		mk := bls.ZeroSignature()
+		for sender, priv := range privs {
+			part, err := group.MembershipKeyPart(priv, recipient)
+			require.NoError(t, err)
+			require.True(t, group.VerifyMembershipKeyPart(part, uint32(sender), recipient))
+			require.False(t, group.VerifyMembershipKeyPart(part, uint32(sender), (recipient+1)%total))
+			mk = mk.Aggregate(part)
+		}
+		require.Equal(t, expected[recipient].Marshal(), mk.Marshal())
+	}
+	require.False(t, group.VerifyMembershipKeyPart(expected[0], total, 0))
+
+	_, err = group.MembershipKeyPart(privs[0], total)
+	require.Equal(t, bls.ErrIndexRange, err)
+	outsider, _ := bls.GenerateRandomKey()
+	_, err = group.MembershipKeyPart(outsider, 0)
+	require.Equal(t, bls.ErrNotMember, err)
+
+	// Members #1 and #3 sign
+	builder, err := group.NewMultisigBuilder(msg)
+	require.NoError(t, err)
+	for _, i := range []uint32{1, 3} {
+		require.NoError(t, builder.Add(i, privs[i].Multisign(msg, group.AggregatedPublicKey(), expected[i])))
+	}
+	multi, err := builder.Build()
+	require.NoError(t, err)
+	require.NoError(t, group.Verify(multi, msg))
+	require.Equal(t, bls.ErrInvalidSignature, group.Verify(multi, []byte("another message")))
+
+	multi.PartMask = new(big.Int).SetBit(multi.PartMask, total, 1)
+	require.Equal(t, bls.ErrIndexRange, group.Verify(multi, msg))
+}
+
+func Test_GroupMarshalBinary(t *testing.T) {
+	group, err := bls.NewGroup(pubs[:5])
+	require.NoError(t, err)
+	data, err := group.MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, data, 4+5*bls.PublicKeySize+1)
+
+	var decoded bls.Group
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	require.Equal(t, group.ID(), decoded.ID())
+	require.Equal(t, group.AggregatedPublicKey().Marshal(), decoded.AggregatedPublicKey().Marshal())
+	require.Equal(t, bls.LegacyMultisigSuite, decoded.Suite())
+
+	// The suite is kept
+	separated, err := group.WithSuite(bls.SeparatedMultisigSuite).MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, separated, len(data)+len(bls.SeparatedMultisigSuite.Tag()))
+	require.NoError(t, decoded.UnmarshalBinary(separated))
+	require.Equal(t, group.ID(), decoded.ID())
+	require.Equal(t, bls.SeparatedMultisigSuite, decoded.Suite())
+
+	require.Equal(t, bls.ErrInvalidLength, decoded.UnmarshalBinary(data[:3]))
+	require.Equal(t, bls.ErrInvalidLength, decoded.UnmarshalBinary(data[:len(data)-1]))
+	require.Equal(t, bls.ErrInvalidLength, decoded.UnmarshalBinary(separated[:len(separated)-1]))
+	require.Equal(t, bls.ErrInvalidLength, decoded.UnmarshalBinary(append(data, 0)))
+	require.Equal(t, bls.ErrNoKeys, decoded.UnmarshalBinary([]byte{0, 0, 0, 0, 0}))
+	duplicate := append(append([]byte{0, 0, 0, 2}, pubs[0].Marshal()...), pubs[0].Marshal()...)
+	require.Equal(t, bls.ErrDuplicateMember, decoded.UnmarshalBinary(append(duplicate, 0)))
+}
+
+func Test_GroupMarshalJSON(t *testing.T) {
+	group, err := bls.NewGroup(pubs[:3])
+	require.NoError(t, err)
+	data, err := json.Marshal(group)
+	require.NoError(t, err)
+
+	var decoded bls.Group
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, group.ID(), decoded.ID())
+
+	// Groups are marshaled by value too, e.g. as struct fields
+	byValue, err := json.Marshal(struct{ Group bls.Group }{*group})
+	require.NoError(t, err)
+	require.Equal(t, `{"Group":`+string(data)+`}`, string(byValue))
+
+	var raw map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &raw))
+	id := group.ID()
+	require.Equal(t, hex.EncodeToString(id[:]), raw["id"])
+	require.NotContains(t, raw, "suite")
+
+	// The suite is kept
+	separated, err := json.Marshal(group.WithSuite(bls.SeparatedMultisigSuite))
+	require.NoError(t, err)
+	require.Contains(t, string(separated), `"suite":"`+bls.SeparatedMultisigSuite.Tag()+`"`)
+	require.NoError(t, json.Unmarshal(separated, &decoded))
+	require.Equal(t, bls.SeparatedMultisigSuite, decoded.Suite())
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, bls.LegacyMultisigSuite, decoded.Suite())
+
+	// The ID is optional, but must match
+	delete(raw, "id")
+	data, err = json.Marshal(raw)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, group.ID(), decoded.ID())
+
+	raw["id"] = hex.EncodeToString(make([]byte, 32))
+	data, err = json.Marshal(raw)
+	require.NoError(t, err)
+	require.Equal(t, bls.ErrGroupID, json.Unmarshal(data, &decoded))
+}
@@ -0,0 +1,75 @@
+package test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/eywa-protocol/bls-crypto/bls"
+	"github.com/stretchr/testify/require"
+)
+
+// hash_to_curve test vectors for BN254G1_XMD:SHA-256_SVDW_RO_
+func Test_HashToCurveVectors(t *testing.T) {
+	cs := bls.NewCiphersuite("QUUX-V01-CS02-with-BN254G1_XMD:SHA-256_SVDW_RO_")
+	vectors := []struct{ msg, point string }{
+		{"", "0a976ab906170db1f9638d376514dbf8c42aef256a54bbd48521f20749e59e86" +
+			"02925ead66b9e68bfc309b014398640ab55f6619ab59bc1fab2210ad4c4d53d5"},
+		{"abc", "23f717bee89b1003957139f193e6be7da1df5f1374b26a4643b0378b5baf53d1" +
+			"04142f826b71ee574452dbc47e05bc3e1a647478403a7ba38b7b93948f4e151d"},
+	}
+	for _, vector := range vectors {
+		point := cs.HashToPoint([]byte(vector.msg))
+		require.Equal(t, vector.point, hex.EncodeToString(point.Marshal()), vector.msg)
+	}
+}
+
+func Test_CiphersuiteDomainSeparation(t *testing.T) {
+	priv, pub := bls.GenerateRandomKey()
+	protocolA := bls.NewCiphersuite("EYWA-PROTOCOL-A-V01_BN254G1_XMD:SHA-256_SVDW_RO_")
+	protocolB := bls.NewCiphersuite("EYWA-PROTOCOL-B-V01_BN254G1_XMD:SHA-256_SVDW_RO_")
+
+	sig := priv.SignWith(protocolA, msg)
+	require.True(t, sig.VerifyWith(protocolA, pub, msg))
+	require.False(t, sig.VerifyWith(protocolB, pub, msg))
+	require.False(t, sig.Verify(pub, msg))
+	require.False(t, priv.Sign(msg).VerifyWith(protocolA, pub, msg))
+
+	require.NotEqual(t, protocolA.HashToPoint(msg).Marshal(), protocolB.HashToPoint(msg).Marshal())
+	require.Equal(t, priv.Sign(msg).Marshal(), bls.LegacyCiphersuite.Sign(priv, msg).Marshal())
+	require.Equal(t, "", bls.LegacyCiphersuite.DST())
+	require.Panics(t, func() { bls.NewCiphersuite("") })
+}
+
+func Test_CiphersuiteAggregation(t *testing.T) {
+	cs := bls.BasicCiphersuite
+	privs, pubs := GenerateRandomKeys(4)
+	msgs := make([][]byte, len(privs))
+	sets := make([]bls.SignatureSet, len(privs))
+	same := bls.ZeroSignature()
+	for i, priv := range privs {
+		msgs[i] = GenRandomBytes(MESSAGE_SIZE)
+		sets[i] = bls.SignatureSet{Pub: pubs[i], Msg: msgs[i], Sig: cs.Sign(priv, msgs[i])}
+		same = same.Aggregate(cs.Sign(priv, msg))
+	}
+
+	require.True(t, cs.FastAggregateVerify(pubs, msg, same))
+	require.False(t, bls.FastAggregateVerify(pubs, msg, same))
+
+	sigs := make([]bls.Signature, len(sets))
+	for i, set := range sets {
+		sigs[i] = set.Sig
+	}
+	sig, err := bls.AggregateSignaturesDistinct(sigs, msgs)
+	require.NoError(t, err)
+	require.True(t, cs.AggregateVerify(pubs, msgs, sig))
+	require.False(t, bls.AggregateVerify(pubs, msgs, sig))
+
+	invalid, err := cs.BatchVerify(sets)
+	require.NoError(t, err)
+	require.Empty(t, invalid)
+	require.Equal(t, []int{0, 1, 2, 3}, batchInvalid(t, sets))
+
+	augmented := bls.AugmentedCiphersuite.SignAugmented(privs[0], msg).
+		Aggregate(bls.AugmentedCiphersuite.SignAugmented(privs[1], msg))
+	require.True(t, bls.AugmentedCiphersuite.AggregateVerifyAugmented(pubs[:2], [][]byte{msg, msg}, augmented))
+}
@@ -2,6 +2,7 @@ package test
 
 import (
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
 	"math/big"
 	"testing"
@@ -11,19 +12,21 @@ import (
 )
 
 type Message struct {
-	Privs      []bls.PrivateKey
+	Privs      []bls.ExportedPrivateKey
 	Multis     []bls.Multisig
 	Mask       *big.Int
-	PrivEmpty  bls.PrivateKey
+	PrivEmpty  bls.ExportedPrivateKey
 	MultiEmpty bls.Multisig
 }
 
 func Test_MarshallUnmarshallJson(t *testing.T) {
 	N := 3
 	privs, pubs := GenerateRandomKeys(N)
+	exported := make([]bls.ExportedPrivateKey, N)
 	signs := make([]bls.Signature, N)
 	multis := make([]bls.Multisig, N)
 	for i, priv := range privs {
+		exported[i] = bls.ExportedPrivateKey(priv)
 		signs[i] = priv.Sign(msg)
 		multis[i] = bls.Multisig{
 			PartSignature: signs[i],
@@ -33,7 +36,7 @@ func Test_MarshallUnmarshallJson(t *testing.T) {
 	}
 
 	outmsg := Message{
-		Privs:  privs,
+		Privs:  exported,
 		Multis: multis,
 	}
 	raw, err := json.Marshal(outmsg)
@@ -46,12 +49,12 @@ func Test_MarshallUnmarshallJson(t *testing.T) {
 	require.Equal(t, len(inmsg.Multis), len(outmsg.Multis))
 	require.Equal(t, inmsg.Multis, outmsg.Multis)
 	for i, _ := range inmsg.Privs {
-		require.Equal(t, 0, bytes.Compare(inmsg.Privs[i].Marshal(), privs[i].Marshal()))
+		require.Equal(t, 0, bytes.Compare(inmsg.Privs[i].PrivateKey().Marshal(), privs[i].Marshal()))
 		require.Equal(t, 0, bytes.Compare(inmsg.Multis[i].PartPublicKey.Marshal(), pubs[i].Marshal()))
 		require.Equal(t, 0, bytes.Compare(inmsg.Multis[i].PartSignature.Marshal(), signs[i].Marshal()))
 		require.Equal(t, inmsg.Multis[i].PartMask.Int64(), int64(i))
 	}
-	require.Equal(t, []byte("0"), inmsg.PrivEmpty.Marshal())
+	require.Nil(t, inmsg.PrivEmpty.PrivateKey().Marshal())
 	require.Equal(t, inmsg.MultiEmpty.PartPublicKey.Marshal(), outmsg.MultiEmpty.PartPublicKey.Marshal())
 	require.Equal(t, inmsg.MultiEmpty.PartSignature.Marshal(), outmsg.MultiEmpty.PartSignature.Marshal())
 	require.Equal(t, inmsg.MultiEmpty.PartMask.String(), outmsg.MultiEmpty.PartMask.String())
@@ -86,3 +89,93 @@ func Test_ReadKeys(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, sig.Marshal(), priv.Sign([]byte("Hello world!")).Marshal())
 }
+
+func Test_PrivateKeyEncodings(t *testing.T) {
+	priv, err := bls.ReadPrivateKey(testPrivateKey)
+	require.NoError(t, err)
+	raw := mustDecodeHex(t, testPrivateKey)
+	require.Equal(t, raw, priv.Marshal())
+
+	text, err := bls.ExportedPrivateKey(priv).MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, testPrivateKey, string(text))
+	data, err := json.Marshal(bls.ExportedPrivateKey(priv))
+	require.NoError(t, err)
+	require.Equal(t, `"`+testPrivateKey+`"`, string(data))
+
+	// All the paths read the same key
+	legacy := []byte(new(big.Int).SetBytes(raw).String())
+	var fromText, fromJSON, fromLegacyJSON bls.PrivateKey
+	require.NoError(t, fromText.UnmarshalText(text))
+	require.NoError(t, json.Unmarshal(data, &fromJSON))
+	require.NoError(t, json.Unmarshal(legacy, &fromLegacyJSON))
+	fromBinary, err := bls.UnmarshalPrivateKey(raw)
+	require.NoError(t, err)
+	fromLegacy, err := bls.UnmarshalPrivateKey(legacy)
+	require.NoError(t, err)
+	fromParse, err := bls.ParsePrivateKey(raw)
+	require.NoError(t, err)
+	for _, key := range []bls.PrivateKey{fromText, fromJSON, fromLegacyJSON, fromBinary, fromLegacy, fromParse} {
+		require.Equal(t, raw, key.Marshal())
+	}
+
+	// Small keys keep the leading zeros
+	small, err := bls.UnmarshalPrivateKey([]byte("255"))
+	require.NoError(t, err)
+	require.Equal(t, append(make([]byte, 31), 0xff), small.Marshal())
+	text, err = bls.ExportedPrivateKey(small).MarshalText()
+	require.NoError(t, err)
+	require.Equal(t, hex.EncodeToString(small.Marshal()), string(text))
+
+	// The decimal numbers of 32 digits are not read as the binary keys
+	digits := []byte("12345678901234567890123456789012")
+	decimal, err := bls.UnmarshalPrivateKey(digits)
+	require.NoError(t, err)
+	expected, _ := new(big.Int).SetString(string(digits), 10)
+	require.Equal(t, expected, decimal.Scalar())
+	require.NoError(t, json.Unmarshal(digits, &fromLegacyJSON))
+	require.Equal(t, expected, fromLegacyJSON.Scalar())
+	digits = []byte("00123456789012345678901234567890")
+	binary, err := bls.ReadPrivateKey(hex.EncodeToString(digits))
+	require.NoError(t, err)
+	require.Equal(t, digits, binary.Marshal())
+
+	order := bls.Order().FillBytes(make([]byte, bls.PrivateKeySize))
+	_, err = bls.UnmarshalPrivateKey(order)
+	require.Equal(t, bls.ErrScalarRange, err)
+	_, err = bls.UnmarshalPrivateKey([]byte(bls.Order().String()))
+	require.Equal(t, bls.ErrScalarRange, err)
+	_, err = bls.ReadPrivateKey(hex.EncodeToString(order))
+	require.Equal(t, bls.ErrScalarRange, err)
+	_, err = bls.ReadPrivateKey(testPrivateKey + "00")
+	require.Equal(t, bls.ErrInvalidLength, err)
+	_, err = bls.ReadPrivateKey("")
+	require.Equal(t, bls.ErrInvalidLength, err)
+	_, err = bls.ReadPrivateKey("1" + hex.EncodeToString(order))
+	require.Equal(t, bls.ErrInvalidLength, err)
+
+	// Shorter hex numbers are padded with the leading zeros
+	for _, str := range []string{"ff", "0ff", "00ff", hex.EncodeToString(small.Marshal())} {
+		priv, err := bls.ReadPrivateKey(str)
+		require.NoError(t, err, str)
+		require.Equal(t, small.Marshal(), priv.Marshal(), str)
+	}
+	below := new(big.Int).Sub(bls.Order(), big.NewInt(1))
+	priv, err = bls.ReadPrivateKey(below.Text(16))
+	require.NoError(t, err)
+	require.Equal(t, below, priv.Scalar())
+	short := new(big.Int).Rsh(bls.Order(), 4)
+	priv, err = bls.ReadPrivateKey(short.Text(16))
+	require.NoError(t, err)
+	require.Equal(t, short, priv.Scalar())
+	var exported bls.ExportedPrivateKey
+	require.NoError(t, exported.UnmarshalText([]byte("ff")))
+	require.Equal(t, small.Marshal(), exported.PrivateKey().Marshal())
+	_, err = bls.UnmarshalPrivateKey([]byte("12a"))
+	require.Equal(t, bls.ErrInvalidLength, err)
+	_, err = bls.UnmarshalPrivateKey(nil)
+	require.Equal(t, bls.ErrInvalidLength, err)
+	var key bls.PrivateKey
+	require.Error(t, json.Unmarshal([]byte(`"hi"`), &key))
+	require.Error(t, json.Unmarshal([]byte(`{}`), &key))
+}
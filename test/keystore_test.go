@@ -0,0 +1,157 @@
+package test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/eywa-protocol/bls-crypto/bls"
+	"github.com/eywa-protocol/bls-crypto/keystore"
+	"github.com/stretchr/testify/require"
+)
+
+// EIP-2335 test vectors (without pubkey, which is a BLS12-381 point there).
+// The password is the NFKD form of "𝔱𝔢𝔰𝔱𝔭𝔞𝔰𝔰𝔴𝔬𝔯𝔡🔑".
+const (
+	eip2335Password = "testpassword🔑"
+	eip2335Secret   = "000000000019d6689c085ae165831e934ff763ae46a2a6c172b3f1b60a8ce26f"
+	eip2335Scrypt   = `{
+		"crypto": {
+			"kdf": {"function": "scrypt", "params": {"dklen": 32, "n": 262144, "p": 1, "r": 8, "salt": "d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3"}, "message": ""},
+			"checksum": {"function": "sha256", "params": {}, "message": "d2217fe5f3e9a1e34581ef8a78f7c9928e436d36dacc5e846690a5581e8ea484"},
+			"cipher": {"function": "aes-128-ctr", "params": {"iv": "264daa3f303d7259501c93d997d84fe6"}, "message": "06ae90d55fe0a6e9c5c3bc5b170827b2e5cce3929ed3f116c2811e6366dfe20f"}
+		},
+		"description": "This is a test keystore that uses scrypt to secure the secret.",
+		"pubkey": "",
+		"path": "m/12381/60/3141592653/589793238",
+		"uuid": "1d85ae20-35c5-4611-98e8-aa14a633906f",
+		"version": 4
+	}`
+	eip2335PBKDF2 = `{
+		"crypto": {
+			"kdf": {"function": "pbkdf2", "params": {"dklen": 32, "c": 262144, "prf": "hmac-sha256", "salt": "d4e56740f876aef8c010b86a40d5f56745a118d0906a34e69aec8c0db1cb8fa3"}, "message": ""},
+			"checksum": {"function": "sha256", "params": {}, "message": "8a9f5d9912ed7e75ea794bc5a89bca5f193721d30868ade6f73043c6ea6febf1"},
+			"cipher": {"function": "aes-128-ctr", "params": {"iv": "264daa3f303d7259501c93d997d84fe6"}, "message": "cee03fde2af33149775b7223e7845e4fb2c8ae1792e5f99fe9ecf474cc8c16ad"}
+		},
+		"description": "This is a test keystore that uses PBKDF2 to secure the secret.",
+		"pubkey": "",
+		"path": "m/12381/60/0/0",
+		"uuid": "64625def-3331-4eea-ab6f-782f3ed16a83",
+		"version": 4
+	}`
+)
+
+var lightScryptOptions = keystore.Options{KDF: keystore.KDFScrypt, ScryptN: 1 << 12, ScryptR: 8, ScryptP: 1}
+
+func Test_KeystoreVectors(t *testing.T) {
+	expected, err := bls.ReadPrivateKey(eip2335Secret)
+	require.NoError(t, err)
+
+	for _, vector := range []string{eip2335Scrypt, eip2335PBKDF2} {
+		ks, err := keystore.Unmarshal([]byte(vector))
+		require.NoError(t, err)
+		priv, err := ks.Decrypt(eip2335Password)
+		require.NoError(t, err)
+		require.Equal(t, expected.Marshal(), priv.Marshal())
+	}
+}
+
+func Test_KeystoreRoundtrip(t *testing.T) {
+	priv, pub := bls.GenerateRandomKey()
+
+	for _, opts := range []keystore.Options{lightScryptOptions, {KDF: keystore.KDFPBKDF2, PBKDF2C: 1 << 10}} {
+		opts.Path = "m/12381/60/0/0"
+		ks, err := keystore.Encrypt(priv, "password", opts)
+		require.NoError(t, err)
+		raw, err := ks.Marshal()
+		require.NoError(t, err)
+
+		restored, err := keystore.Unmarshal(raw)
+		require.NoError(t, err)
+		require.Equal(t, "m/12381/60/0/0", restored.Path)
+		require.Equal(t, ks.UUID, restored.UUID)
+		stored, err := restored.PublicKey()
+		require.NoError(t, err)
+		require.Equal(t, pub.Marshal(), stored.Marshal())
+
+		decrypted, err := restored.Decrypt("password")
+		require.NoError(t, err)
+		require.Equal(t, priv.Marshal(), decrypted.Marshal())
+	}
+}
+
+func Test_KeystoreWrongPassword(t *testing.T) {
+	priv, _ := bls.GenerateRandomKey()
+	ks, err := keystore.Encrypt(priv, "password", lightScryptOptions)
+	require.NoError(t, err)
+
+	_, err = ks.Decrypt("passw0rd")
+	require.True(t, errors.Is(err, keystore.ErrInvalidPassword))
+	require.False(t, errors.Is(err, keystore.ErrInvalidKeystore))
+
+	// Control codes are stripped from the password
+	_, err = ks.Decrypt("pass\x7fword\n")
+	require.NoError(t, err)
+}
+
+func Test_KeystoreCorrupted(t *testing.T) {
+	priv, _ := bls.GenerateRandomKey()
+	_, otherPub := bls.GenerateRandomKey()
+
+	corruptions := []func(ks *keystore.Keystore){
+		func(ks *keystore.Keystore) { ks.Crypto.Cipher.Message = "zz" },
+		func(ks *keystore.Keystore) { ks.Crypto.Cipher.Message = ks.Crypto.Cipher.Message[2:] },
+		func(ks *keystore.Keystore) { ks.Crypto.Cipher.Function = "aes-256-gcm" },
+		func(ks *keystore.Keystore) { ks.Crypto.Checksum.Message = "00" },
+		func(ks *keystore.Keystore) { ks.Crypto.KDF.Function = "argon2" },
+		func(ks *keystore.Keystore) { ks.Crypto.KDF.Params = []byte(`{"n": "many"}`) },
+		func(ks *keystore.Keystore) { setKDFParam(t, ks, "dklen", 31) },
+		func(ks *keystore.Keystore) { setKDFParam(t, ks, "dklen", 1<<30) },
+		func(ks *keystore.Keystore) { setKDFParam(t, ks, "n", 1<<21) },
+		func(ks *keystore.Keystore) { setKDFParam(t, ks, "n", 1<<40) },
+		func(ks *keystore.Keystore) { setKDFParam(t, ks, "n", 3000) },
+		func(ks *keystore.Keystore) { setKDFParam(t, ks, "r", 1<<20) },
+		func(ks *keystore.Keystore) { setKDFParam(t, ks, "p", 1<<30) },
+		func(ks *keystore.Keystore) { setKDFParam(t, ks, "r", 16); setKDFParam(t, ks, "p", 8) },
+		func(ks *keystore.Keystore) { setKDFParam(t, ks, "r", 16); setKDFParam(t, ks, "n", 1<<20) },
+		func(ks *keystore.Keystore) { ks.Pubkey = hex.EncodeToString(otherPub.Marshal()) },
+		func(ks *keystore.Keystore) { ks.Version = 3 },
+	}
+	for i, corrupt := range corruptions {
+		ks, err := keystore.Encrypt(priv, "password", lightScryptOptions)
+		require.NoError(t, err)
+		corrupt(ks)
+		_, err = ks.Decrypt("password")
+		require.True(t, errors.Is(err, keystore.ErrInvalidKeystore), "corruption #%d: %v", i, err)
+	}
+
+	// The PBKDF2 parameters are bounded too
+	for _, params := range []map[string]interface{}{{"c": 1 << 40}, {"c": 0}, {"dklen": 64}} {
+		ks, err := keystore.Encrypt(priv, "password", keystore.Options{KDF: keystore.KDFPBKDF2, PBKDF2C: 1 << 10})
+		require.NoError(t, err)
+		for name, value := range params {
+			setKDFParam(t, ks, name, value)
+		}
+		_, err = ks.Decrypt("password")
+		require.True(t, errors.Is(err, keystore.ErrInvalidKeystore), "%v: %v", params, err)
+	}
+
+	_, err := keystore.Encrypt(priv, "password", keystore.Options{KDF: keystore.KDFScrypt, ScryptN: 1 << 21, ScryptR: 8, ScryptP: 1})
+	require.True(t, errors.Is(err, keystore.ErrInvalidKeystore))
+
+	_, err = keystore.Unmarshal([]byte("{"))
+	require.True(t, errors.Is(err, keystore.ErrInvalidKeystore))
+	_, err = keystore.Unmarshal([]byte(`{"version": 3}`))
+	require.True(t, errors.Is(err, keystore.ErrInvalidKeystore))
+}
+
+// setKDFParam overrides the parameter of the KDF module
+func setKDFParam(t *testing.T, ks *keystore.Keystore, name string, value interface{}) {
+	var params map[string]interface{}
+	require.NoError(t, json.Unmarshal(ks.Crypto.KDF.Params, &params))
+	params[name] = value
+	data, err := json.Marshal(params)
+	require.NoError(t, err)
+	ks.Crypto.KDF.Params = data
+}
@@ -0,0 +1,106 @@
+package test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/eywa-protocol/bls-crypto/mnemonic"
+	"github.com/stretchr/testify/require"
+)
+
+// BIP-39 test vectors (passphrase "TREZOR")
+var mnemonicVectors = []struct {
+	entropy, mnemonic, seed string
+}{
+	{
+		"00000000000000000000000000000000",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+		"c55257c360c07c72029aebc1b53c05ed0362ada38ead3e3e9efa3708e53495531f09a6987599d18264c1e1c92f2cf141630c7a3c4ab7c81b2f001698e7463b04",
+	},
+	{
+		"7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f7f",
+		"legal winner thank year wave sausage worth useful legal winner thank yellow",
+		"2e8905819b8723fe2c1d161860e5ee1830318dbf49a83bd451cfb8440c28bd6fa457fe1296106559a3c80937a1c1069be3a3a5bd381ee6260e8d9739fce1f607",
+	},
+	{
+		"808080808080808080808080808080808080808080808080",
+		"letter advice cage absurd amount doctor acoustic avoid letter advice cage absurd amount doctor acoustic avoid letter always",
+		"107d7c02a5aa6f38c58083ff74f04c607c2d2c0ecc55501dadd72d025b751bc27fe913ffb796f841c49b1d33b610cf0e91d3aa239027f5e99fe4ce9e5088cd65",
+	},
+	{
+		"ffffffffffffffffffffffffffffffffffffffffffffffff",
+		"zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo when",
+		"0cd6e5d827bb62eb8fc1e262254223817fd068a74b5b449cc2f667c3f1f985a76379b43348d952e2265b4cd129090758b3e3c2c49103b5051aac2eaeb890a528",
+	},
+	{
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon art",
+		"bda85446c68413707090a52022edd26a1c9462295029f2e60cd7c4f2bbd3097170af7a4d73245cafa9c3cca8d561a7c3de6f5d4a10be8ed2a5e608d68f92fcc8",
+	},
+}
+
+func Test_MnemonicVectors(t *testing.T) {
+	for _, vector := range mnemonicVectors {
+		entropy, err := hex.DecodeString(vector.entropy)
+		require.NoError(t, err)
+
+		words, err := mnemonic.NewMnemonic(entropy)
+		require.NoError(t, err)
+		require.Equal(t, vector.mnemonic, words)
+
+		decoded, err := mnemonic.EntropyFromMnemonic(words)
+		require.NoError(t, err)
+		require.Equal(t, entropy, decoded)
+
+		seed, err := mnemonic.Seed(words, "TREZOR")
+		require.NoError(t, err)
+		require.Equal(t, vector.seed, hex.EncodeToString(seed))
+	}
+}
+
+func Test_MnemonicNormalization(t *testing.T) {
+	// The passphrase is normalized to NFKD: the composed and the compatibility
+	// characters give the same seed as the decomposed ones
+	words := mnemonicVectors[0].mnemonic
+	composed := "\u334d\u30ac\u30d0\u30f4\u30a1\u3071\u3070\u3050\u309e\u3061\u3062\u5341\u4eba\u5341\u8272"
+	decomposed := "\u30e1\u30fc\u30c8\u30eb\u30ab\u3099\u30cf\u3099\u30a6\u3099\u30a1\u306f\u309a\u306f\u3099\u304f\u3099\u309d\u3099\u3061\u3061\u3099\u5341\u4eba\u5341\u8272"
+	for _, passphrase := range []string{composed, decomposed} {
+		seed, err := mnemonic.Seed(words, passphrase)
+		require.NoError(t, err)
+		require.Equal(t, "ba553eedefe76e67e2602dc20184c564010859faada929a090dd2c57aacb204ceefd15404ab50ef3e8dbeae5195aeae64b0def4d2eead1cdc728a33ced520ffd", hex.EncodeToString(seed))
+	}
+}
+
+func Test_MnemonicRestoreKey(t *testing.T) {
+	// The seed of the first vector is the one used in Test_DeriveKeyVectors
+	priv, err := mnemonic.PrivateKey(mnemonicVectors[0].mnemonic, "TREZOR", mnemonic.DefaultPath)
+	require.NoError(t, err)
+	requireKeyEqual(t, "9858384286953664294113346328707988549089656255211929188011147946764474537278", priv)
+
+	words, priv, pub, err := mnemonic.GenerateKey("passphrase")
+	require.NoError(t, err)
+	require.NoError(t, mnemonic.Validate(words))
+	restored, err := mnemonic.PrivateKey(words, "passphrase", mnemonic.DefaultPath)
+	require.NoError(t, err)
+	require.Equal(t, priv.Marshal(), restored.Marshal())
+	require.Equal(t, pub.Marshal(), restored.PublicKey().Marshal())
+
+	other, err := mnemonic.PrivateKey(words, "", mnemonic.DefaultPath)
+	require.NoError(t, err)
+	require.NotEqual(t, priv.Marshal(), other.Marshal())
+}
+
+func Test_MnemonicInvalid(t *testing.T) {
+	_, err := mnemonic.NewEntropy(100)
+	require.Equal(t, mnemonic.ErrEntropySize, err)
+	_, err = mnemonic.NewMnemonic(make([]byte, 15))
+	require.Equal(t, mnemonic.ErrEntropySize, err)
+
+	require.Equal(t, mnemonic.ErrWordCount, mnemonic.Validate("abandon abandon about"))
+	require.Equal(t, mnemonic.ErrUnknownWord, mnemonic.Validate("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon bitcoin"))
+	require.Equal(t, mnemonic.ErrChecksum, mnemonic.Validate("abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon"))
+	require.Equal(t, mnemonic.ErrChecksum, mnemonic.Validate("zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo"))
+
+	_, err = mnemonic.PrivateKey("abandon abandon about", "", mnemonic.DefaultPath)
+	require.Error(t, err)
+}
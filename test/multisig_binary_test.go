@@ -0,0 +1,84 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/eywa-protocol/bls-crypto/bls"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_MultisigMarshalBinary(t *testing.T) {
+	for _, mask := range []*big.Int{
+		big.NewInt(0b1),
+		big.NewInt(0b100101),
+		new(big.Int).SetBit(big.NewInt(1), len(pubs)-1, 1),
+	} {
+		pub, sig := signMultisigPartially(mask)
+		multi := bls.Multisig{PartSignature: sig, PartPublicKey: pub, PartMask: mask}
+		data, err := multi.MarshalBinary()
+		require.NoError(t, err)
+		require.Len(t, data, 1+32+64+4+len(mask.Bytes()))
+		require.Equal(t, byte(bls.MultisigVersion), data[0])
+
+		var decoded bls.Multisig
+		require.NoError(t, decoded.UnmarshalBinary(data))
+		require.Equal(t, sig.Marshal(), decoded.PartSignature.Marshal())
+		require.Equal(t, pub.Marshal(), decoded.PartPublicKey.Marshal())
+		require.Equal(t, 0, mask.Cmp(decoded.PartMask))
+		require.True(t, decoded.Verify(aggPub, msg))
+	}
+
+	// The zero multisignature has points at infinity and an empty bitmask
+	data, err := bls.NewZeroMultisig().MarshalBinary()
+	require.NoError(t, err)
+	require.Len(t, data, 1+32+64+4)
+	var decoded bls.Multisig
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	require.NotNil(t, decoded.PartMask)
+	require.Zero(t, decoded.PartMask.Sign())
+
+	_, err = bls.Multisig{PartPublicKey: pubs[0], PartMask: big.NewInt(1)}.MarshalBinary()
+	require.Equal(t, bls.ErrNilSignature, err)
+	_, err = bls.Multisig{PartSignature: bls.ZeroSignature(), PartMask: big.NewInt(1)}.MarshalBinary()
+	require.Equal(t, bls.ErrNilKey, err)
+	_, err = bls.Multisig{PartSignature: bls.ZeroSignature(), PartPublicKey: pubs[0]}.MarshalBinary()
+	require.Equal(t, bls.ErrInvalidMask, err)
+	_, err = bls.Multisig{PartSignature: bls.ZeroSignature(), PartPublicKey: pubs[0], PartMask: big.NewInt(-1)}.MarshalBinary()
+	require.Equal(t, bls.ErrInvalidMask, err)
+}
+
+func Test_MultisigUnmarshalBinaryMalformed(t *testing.T) {
+	mask := big.NewInt(0x1234)
+	pub, sig := signMultisigPartially(mask)
+	data, err := bls.Multisig{PartSignature: sig, PartPublicKey: pub, PartMask: mask}.MarshalBinary()
+	require.NoError(t, err)
+	modified := func(offset int, b byte) []byte {
+		res := append([]byte{}, data...)
+		res[offset] = b
+		return res
+	}
+	// with a leading zero byte of the bitmask
+	leadingZero := append(append([]byte{}, data[:97]...), 0, 0, 0, 3, 0)
+	leadingZero = append(leadingZero, mask.Bytes()...)
+
+	cases := []struct {
+		data []byte
+		err  error
+	}{
+		{nil, bls.ErrInvalidLength},
+		{data[:100], bls.ErrInvalidLength},
+		{data[:len(data)-1], bls.ErrInvalidLength},
+		{append(append([]byte{}, data...), 0), bls.ErrInvalidLength},
+		{modified(0, 0), bls.ErrUnsupportedVersion},
+		{modified(0, 2), bls.ErrUnsupportedVersion},
+		{modified(1, data[1]&0x3f), bls.ErrNonCanonical},
+		{modified(33, data[33]&0x3f), bls.ErrNonCanonical},
+		{modified(100, 3), bls.ErrInvalidLength},
+		{leadingZero, bls.ErrNonCanonical},
+	}
+	for i, c := range cases {
+		var decoded bls.Multisig
+		require.Equal(t, c.err, decoded.UnmarshalBinary(c.data), "case %d", i)
+	}
+}
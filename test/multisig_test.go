@@ -147,3 +147,96 @@ func Test_MembershipKeysInSolidity(t *testing.T) {
 	verifiedSol, err := blsSignatureTest.Verified(&bind.CallOpts{})
 	require.True(t, verifiedSol)
 }
+
+func Test_VerifyMultisigAbove256(t *testing.T) {
+	const total = 300
+	privs, pubs := GenerateRandomKeys(total)
+	coefs := bls.CalculateAntiRogueCoefficients(pubs)
+	allPub := bls.AggregatePublicKeys(pubs, coefs)
+
+	// Sign by #3, #258 and #299 only
+	signers := []int{3, 258, 299}
+	mask := big.NewInt(0)
+	subPub, subSig := bls.ZeroPublicKey(), bls.ZeroSignature()
+	for _, i := range signers {
+		mk := bls.ZeroSignature()
+		for j := range privs {
+			mk = mk.Aggregate(privs[j].GenerateMembershipKeyPart(uint32(i), allPub, coefs[j]))
+		}
+		mask.SetBit(mask, i, 1)
+		subPub = subPub.Aggregate(pubs[i])
+		subSig = subSig.Aggregate(privs[i].Multisign(msg, allPub, mk))
+	}
+
+	multi := bls.Multisig{PartSignature: subSig, PartPublicKey: subPub, PartMask: mask}
+	require.True(t, multi.Verify(allPub, msg))
+	// #258 collides with #2 in the legacy byte-wrapped mode
+	require.False(t, multi.VerifyLegacy(allPub, msg))
+
+	words := bls.MultisigMaskWords(mask)
+	require.Len(t, words, 2)
+	require.Equal(t, big.NewInt(1<<3), words[0])
+	require.Equal(t, new(big.Int).SetBit(big.NewInt(1<<2), 299-256, 1), words[1])
+
+	_, err := blsSignatureTest.VerifyMultisignatureWide(owner, allPub.Marshal(), subPub.Marshal(), msg, subSig.Marshal(), words)
+	require.NoError(t, err)
+	backend.Commit()
+	verifiedSol, err := blsSignatureTest.Verified(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.True(t, verifiedSol)
+}
+
+func Test_VerifyMultisigLegacy(t *testing.T) {
+	// Groups of up to 256 members verify the same in the legacy mode
+	mask := big.NewInt(0b1101)
+	pub, sig := signMultisigPartially(mask)
+	multi := bls.Multisig{PartSignature: sig, PartPublicKey: pub, PartMask: mask}
+	require.True(t, multi.Verify(aggPub, msg))
+	require.True(t, multi.VerifyLegacy(aggPub, msg))
+	require.Empty(t, bls.MultisigMaskWords(big.NewInt(0)))
+}
+
+func Test_MultisigDomainSeparation(t *testing.T) {
+	one, zero := *big.NewInt(1), bls.ZeroSignature()
+	// The membership index 5 encoded as a 32-byte message
+	index := make([]byte, 32)
+	index[31] = 5
+
+	// The legacy H(P, m) of the index is H(P, i), so the membership key part
+	// is the partial signature of the index as a message
+	require.Equal(t, privs[0].GenerateMembershipKeyPart(5, aggPub, one).Marshal(),
+		privs[0].Multisign(index, aggPub, zero).Marshal())
+	// and the multisignature by the single member is the augmented signature
+	require.Equal(t, bls.LegacyCiphersuite.SignAugmented(privs[0], msg).Marshal(),
+		privs[0].Multisign(msg, pubs[0], zero).Marshal())
+
+	suite := bls.SeparatedMultisigSuite
+	require.NotEqual(t, suite.GenerateMembershipKeyPart(privs[0], 5, aggPub, one).Marshal(),
+		suite.Multisign(privs[0], index, aggPub, zero).Marshal())
+	require.NotEqual(t, bls.LegacyCiphersuite.SignAugmented(privs[0], msg).Marshal(),
+		suite.Multisign(privs[0], msg, pubs[0], zero).Marshal())
+	require.NotEqual(t, bls.AugmentedCiphersuite.SignAugmented(privs[0], msg).Marshal(),
+		suite.Multisign(privs[0], msg, pubs[0], zero).Marshal())
+	require.Equal(t, bls.HashToPointIndex(aggPub, 5).Marshal(), bls.LegacyMultisigSuite.HashToPointIndex(aggPub, 5).Marshal())
+	require.NotEqual(t, suite.HashToPointIndex(aggPub, 5).Marshal(), bls.HashToPointIndex(aggPub, 5).Marshal())
+
+	// Multisignatures of one suite do not verify by another
+	mask := big.NewInt(0b1101)
+	separatedMks := AggregateMembershipKeysWith(suite, privs, pubs, aggPub, as)
+	pub, sig := bls.ZeroPublicKey(), bls.ZeroSignature()
+	for i := range pubs {
+		if mask.Bit(i) != 0 {
+			sig = sig.Aggregate(suite.Multisign(privs[i], msg, aggPub, separatedMks[i]))
+			pub = pub.Aggregate(pubs[i])
+		}
+	}
+	multi := bls.Multisig{PartSignature: sig, PartPublicKey: pub, PartMask: mask}
+	require.True(t, suite.Verify(multi, aggPub, msg))
+	require.False(t, multi.Verify(aggPub, msg))
+	require.False(t, bls.NewMultisigSuite("OTHER_").Verify(multi, aggPub, msg))
+
+	legacyPub, legacySig := signMultisigPartially(mask)
+	legacy := bls.Multisig{PartSignature: legacySig, PartPublicKey: legacyPub, PartMask: mask}
+	require.True(t, legacy.Verify(aggPub, msg))
+	require.False(t, suite.Verify(legacy, aggPub, msg))
+}
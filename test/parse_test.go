@@ -0,0 +1,129 @@
+package test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	bn256 "github.com/ethereum/go-ethereum/crypto/bn256/cloudflare"
+	"github.com/eywa-protocol/bls-crypto/bls"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// twistPointOutOfG2 is a point of the twist curve which is not in G2
+	twistPointOutOfG2 = "0000000000000000000000000000000000000000000000000000000000000001" +
+		"0000000000000000000000000000000000000000000000000000000000000002" +
+		"2b76c179599bb92a963dac85546a005a777f7c13f6a7b75d5918b6b5808f5fde" +
+		"101f7278419308b95099eca02dcee0c5381f4d26d1d62313f057167f064101ce"
+	testPrivateKey = "194cd886f74a0a5a064d24855dea732bf1474954b61ecb0ee55b4fb58b7346b5"
+)
+
+func mustDecodeHex(t *testing.T, str string) []byte {
+	raw, err := hex.DecodeString(str)
+	require.NoError(t, err)
+	return raw
+}
+
+// withWord replaces the 32-byte word of the encoding
+func withWord(raw []byte, index int, word []byte) []byte {
+	res := append([]byte{}, raw...)
+	copy(res[index*32:(index+1)*32], bytes.Repeat([]byte{0}, 32))
+	copy(res[(index+1)*32-len(word):], word)
+	return res
+}
+
+func Test_ParsePublicKey(t *testing.T) {
+	pub, err := bls.ParsePublicKey(publicKey.Marshal())
+	require.NoError(t, err)
+	require.Equal(t, publicKey.Marshal(), pub.Marshal())
+
+	raw := publicKey.Marshal()
+	malformed := []struct {
+		raw []byte
+		err error
+	}{
+		{nil, bls.ErrInvalidLength},
+		{[]byte{}, bls.ErrInvalidLength},
+		{raw[:127], bls.ErrInvalidLength},
+		{append(append([]byte{}, raw...), 0), bls.ErrInvalidLength},
+		{make([]byte, bls.PublicKeySize), bls.ErrInfinity},
+		{withWord(raw, 0, bn256.P.Bytes()), bls.ErrNonCanonical},
+		{withWord(raw, 3, bytes.Repeat([]byte{0xff}, 32)), bls.ErrNonCanonical},
+		{withWord(raw, 3, big.NewInt(1).Bytes()), bls.ErrNotOnCurve},
+		{mustDecodeHex(t, twistPointOutOfG2), bls.ErrNotInSubgroup},
+	}
+	for i, test := range malformed {
+		_, err := bls.ParsePublicKey(test.raw)
+		require.Equal(t, test.err, err, "case #%d", i)
+	}
+}
+
+func Test_ParseSignature(t *testing.T) {
+	sig, err := bls.ParseSignature(signature.Marshal())
+	require.NoError(t, err)
+	require.Equal(t, signature.Marshal(), sig.Marshal())
+
+	generator := withWord(withWord(make([]byte, 64), 0, []byte{1}), 1, []byte{2})
+	_, err = bls.ParseSignature(generator)
+	require.NoError(t, err)
+
+	raw := signature.Marshal()
+	malformed := []struct {
+		raw []byte
+		err error
+	}{
+		{nil, bls.ErrInvalidLength},
+		{raw[:63], bls.ErrInvalidLength},
+		{append(append([]byte{}, raw...), raw...), bls.ErrInvalidLength},
+		{make([]byte, bls.SignatureSize), bls.ErrInfinity},
+		{withWord(raw, 0, bn256.P.Bytes()), bls.ErrNonCanonical},
+		{withWord(raw, 1, new(big.Int).Add(bn256.P, big.NewInt(2)).Bytes()), bls.ErrNonCanonical},
+		{withWord(generator, 1, []byte{3}), bls.ErrNotOnCurve},
+		{withWord(raw, 1, big.NewInt(1).Bytes()), bls.ErrNotOnCurve},
+	}
+	for i, test := range malformed {
+		_, err := bls.ParseSignature(test.raw)
+		require.Equal(t, test.err, err, "case #%d", i)
+	}
+}
+
+func Test_ParsePrivateKey(t *testing.T) {
+	raw := mustDecodeHex(t, testPrivateKey)
+	priv, err := bls.ParsePrivateKey(raw)
+	require.NoError(t, err)
+	expected, err := bls.ReadPrivateKey(testPrivateKey)
+	require.NoError(t, err)
+	require.Equal(t, expected.PublicKey().Marshal(), priv.PublicKey().Marshal())
+
+	maxKey := new(big.Int).Sub(bn256.Order, big.NewInt(1)).Bytes()
+	_, err = bls.ParsePrivateKey(maxKey)
+	require.NoError(t, err)
+
+	malformed := []struct {
+		raw []byte
+		err error
+	}{
+		{nil, bls.ErrInvalidLength},
+		{raw[1:], bls.ErrInvalidLength},
+		{append([]byte{0}, raw...), bls.ErrInvalidLength},
+		{make([]byte, bls.PrivateKeySize), bls.ErrScalarRange},
+		{bn256.Order.Bytes(), bls.ErrScalarRange},
+		{new(big.Int).Add(bn256.Order, big.NewInt(1)).Bytes(), bls.ErrScalarRange},
+		{bytes.Repeat([]byte{0xff}, 32), bls.ErrScalarRange},
+	}
+	for i, test := range malformed {
+		_, err := bls.ParsePrivateKey(test.raw)
+		require.Equal(t, test.err, err, "case #%d", i)
+	}
+
+	_, err = bls.PrivateKeyFromScalar(bls.Order())
+	require.Equal(t, bls.ErrScalarRange, err)
+	// The order is a copy the callers can not alter
+	bls.Order().SetInt64(1)
+	require.Equal(t, bn256.Order, bls.Order())
+	fromScalar, err := bls.PrivateKeyFromScalar(priv.Scalar())
+	require.NoError(t, err)
+	require.Equal(t, priv.PublicKey().Marshal(), fromScalar.PublicKey().Marshal())
+}
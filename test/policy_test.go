@@ -0,0 +1,55 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/eywa-protocol/bls-crypto/bls"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_VerifyWithPolicy(t *testing.T) {
+	// 43 of 64 signed: #0..#42
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 43), big.NewInt(1))
+	pub, sig := signMultisigPartially(mask)
+	multi := bls.Multisig{PartSignature: sig, PartPublicKey: pub, PartMask: mask}
+	twoThirds := big.NewRat(2, 3)
+
+	tests := []struct {
+		policy  bls.Policy
+		failed  bls.PolicyRule
+		missing []uint32
+	}{
+		{bls.Policy{}, bls.RuleNone, nil},
+		{bls.Policy{GroupSize: len(pubs), MinSigners: 43, MinFraction: twoThirds, Required: []uint32{0, 42}}, bls.RuleNone, nil},
+		{bls.Policy{GroupSize: 42}, bls.RuleGroupSize, nil},
+		{bls.Policy{MinSigners: 44}, bls.RuleMinSigners, nil},
+		{bls.Policy{GroupSize: len(pubs), MinFraction: big.NewRat(3, 4)}, bls.RuleFraction, nil},
+		{bls.Policy{MinFraction: twoThirds}, bls.RuleFraction, nil},
+		// The fraction of the unknown group size fails even if it is zero
+		{bls.Policy{MinFraction: new(big.Rat)}, bls.RuleFraction, nil},
+		{bls.Policy{Required: []uint32{1, 43, 50}}, bls.RuleRequired, []uint32{43, 50}},
+	}
+	for i, test := range tests {
+		result := multi.VerifyWithPolicy(aggPub, msg, test.policy)
+		require.Equal(t, test.failed, result.Failed, "case #%d: %s", i, result.Failed)
+		require.Equal(t, test.failed == bls.RuleNone, result.OK(), "case #%d", i)
+		require.Equal(t, 43, result.Signers, "case #%d", i)
+		require.Equal(t, test.missing, result.Missing, "case #%d", i)
+	}
+
+	// The policy is satisfied, but the signature is not valid
+	result := multi.VerifyWithPolicy(aggPub, GenRandomBytes(MESSAGE_SIZE), bls.Policy{MinFraction: twoThirds, GroupSize: len(pubs)})
+	require.Equal(t, bls.RuleSignature, result.Failed)
+	require.Equal(t, "signature", result.Failed.String())
+
+	// A single signer passes Verify, but not the policy
+	pub, sig = signMultisigPartially(big.NewInt(1))
+	single := bls.Multisig{PartSignature: sig, PartPublicKey: pub, PartMask: big.NewInt(1)}
+	require.True(t, single.Verify(aggPub, msg))
+	result = single.VerifyWithPolicy(aggPub, msg, bls.Policy{GroupSize: len(pubs), MinFraction: twoThirds})
+	require.False(t, result.OK())
+	require.Equal(t, bls.RuleFraction, result.Failed)
+
+	require.Equal(t, bls.RuleSignature, bls.Multisig{}.VerifyWithPolicy(aggPub, msg, bls.Policy{}).Failed)
+}
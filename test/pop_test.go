@@ -0,0 +1,54 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/eywa-protocol/bls-crypto/bls"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ProofOfPossession(t *testing.T) {
+	priv, pub := bls.GenerateRandomKey()
+	_, otherPub := bls.GenerateRandomKey()
+
+	proof := priv.ProvePossession()
+	require.True(t, pub.VerifyPossession(proof))
+	require.False(t, otherPub.VerifyPossession(proof))
+
+	// A proof is not a signature of the public key bytes and vice versa
+	require.False(t, pub.VerifyPossession(priv.Sign(pub.Marshal())))
+	require.False(t, proof.Verify(pub, pub.Marshal()))
+}
+
+func Test_ProofOfPossessionInSolidity(t *testing.T) {
+	priv, pub := bls.GenerateRandomKey()
+	proof := priv.ProvePossession()
+
+	_, err := blsSignatureTest.VerifyPossessionProof(owner, pub.Marshal(), proof.Marshal())
+	require.NoError(t, err)
+	backend.Commit()
+	verifiedSol, err := blsSignatureTest.Verified(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.True(t, verifiedSol)
+
+	_, err = blsSignatureTest.VerifyPossessionProof(owner, pub.Marshal(), priv.Sign(msg).Marshal())
+	require.NoError(t, err)
+	backend.Commit()
+	verifiedSol, err = blsSignatureTest.Verified(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.False(t, verifiedSol)
+}
+
+func Test_FastAggregateVerify(t *testing.T) {
+	privs, pubs := GenerateRandomKeys(8)
+	sig := bls.ZeroSignature()
+	for _, priv := range privs {
+		require.True(t, priv.PublicKey().VerifyPossession(priv.ProvePossession()))
+		sig = sig.Aggregate(priv.Sign(msg))
+	}
+	require.True(t, bls.FastAggregateVerify(pubs, msg, sig))
+	require.False(t, bls.FastAggregateVerify(pubs[1:], msg, sig))
+	require.False(t, bls.FastAggregateVerify(pubs, GenRandomBytes(MESSAGE_SIZE), sig))
+	require.False(t, bls.FastAggregateVerify(nil, msg, sig))
+}
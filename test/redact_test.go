@@ -0,0 +1,75 @@
+package test
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/eywa-protocol/bls-crypto/bls"
+	"github.com/eywa-protocol/bls-crypto/threshold"
+	"github.com/stretchr/testify/require"
+)
+
+// requireNoSecret checks that the output has the key in neither hex nor
+// decimal form
+func requireNoSecret(t *testing.T, priv bls.PrivateKey, output string) {
+	scalar := new(big.Int).SetBytes(priv.Marshal())
+	require.NotContains(t, strings.ToLower(output), fmt.Sprintf("%x", scalar))
+	require.NotContains(t, strings.ToLower(output), fmt.Sprintf("%064x", scalar))
+	require.NotContains(t, output, scalar.String())
+}
+
+func Test_PrivateKeyRedactedJSON(t *testing.T) {
+	priv, _ := bls.GenerateRandomKey()
+	data, err := json.Marshal(priv)
+	require.NoError(t, err)
+	require.Equal(t, `"REDACTED"`, string(data))
+
+	// Keys embedded in structures are redacted too
+	share := threshold.Share{Index: 1, Key: priv}
+	data, err = json.Marshal(struct {
+		Share threshold.Share
+		Keys  map[string]bls.PrivateKey
+	}{share, map[string]bls.PrivateKey{"key": priv}})
+	require.NoError(t, err)
+	requireNoSecret(t, priv, string(data))
+	text, err := priv.MarshalText()
+	require.NoError(t, err)
+	requireNoSecret(t, priv, string(text))
+
+	empty, err := json.Marshal(bls.PrivateKey{})
+	require.NoError(t, err)
+	require.Equal(t, "null", string(empty))
+
+	// The redacted key is not read back as a key
+	var decoded bls.PrivateKey
+	require.Equal(t, bls.ErrRedacted, json.Unmarshal([]byte(`"REDACTED"`), &decoded))
+
+	// Exported keys are read by both types
+	data, err = json.Marshal(bls.ExportedPrivateKey(priv))
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	require.Equal(t, priv.Marshal(), decoded.Marshal())
+	var exported bls.ExportedPrivateKey
+	require.NoError(t, json.Unmarshal(data, &exported))
+	require.Equal(t, priv.Marshal(), exported.PrivateKey().Marshal())
+}
+
+func Test_PrivateKeyRedactedFormat(t *testing.T) {
+	priv, _ := bls.GenerateRandomKey()
+	for _, format := range []string{"%v", "%+v", "%#v", "%s", "%q", "%x", "%X", "%d", "%08d"} {
+		output := fmt.Sprintf(format, priv)
+		require.Contains(t, output, "REDACTED", format)
+		requireNoSecret(t, priv, output)
+		output = fmt.Sprintf(format, []bls.PrivateKey{priv})
+		requireNoSecret(t, priv, output)
+		output = fmt.Sprintf(format, threshold.Share{Index: 1, Key: priv})
+		requireNoSecret(t, priv, output)
+		output = fmt.Sprintf(format, &threshold.Share{Index: 1, Key: priv})
+		requireNoSecret(t, priv, output)
+	}
+	require.Equal(t, "bls.PrivateKey(REDACTED)", priv.String())
+	require.Equal(t, "bls.PrivateKey{REDACTED}", fmt.Sprintf("%#v", priv))
+}
@@ -0,0 +1,176 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/eywa-protocol/bls-crypto/bls"
+	"github.com/eywa-protocol/bls-crypto/dkg"
+	"github.com/eywa-protocol/bls-crypto/threshold"
+	"github.com/stretchr/testify/require"
+)
+
+// newReshareNodes creates the nodes of both committees. The old members
+// listed in lost have lost their shares and do not deal.
+func newReshareNodes(t *testing.T, shares []threshold.Share, commitments threshold.Commitments, newParticipants []uint32, newThreshold int, lost ...uint32) []*dkg.Node {
+	oldParticipants := make([]uint32, len(shares))
+	for i, share := range shares {
+		oldParticipants[i] = share.Index
+	}
+	var nodes []*dkg.Node
+	for _, index := range union(oldParticipants, newParticipants) {
+		config := dkg.ReshareConfig{
+			Index:           index,
+			OldParticipants: oldParticipants,
+			OldCommitments:  commitments,
+			NewParticipants: newParticipants,
+			NewThreshold:    newThreshold,
+		}
+		for i := range shares {
+			if shares[i].Index == index && !containsIndex(lost, index) {
+				config.Share = &shares[i]
+			}
+		}
+		node, err := dkg.NewReshareNode(config)
+		require.NoError(t, err)
+		nodes = append(nodes, node)
+	}
+	return nodes
+}
+
+// newResults picks the results of the new committee
+func newResults(nodes []*dkg.Node, results []*dkg.Result, newParticipants []uint32) []*dkg.Result {
+	var res []*dkg.Result
+	for i, node := range nodes {
+		if containsIndex(newParticipants, node.Index()) {
+			res = append(res, results[i])
+		}
+	}
+	return res
+}
+
+func union(a, b []uint32) []uint32 {
+	res := append([]uint32{}, a...)
+	for _, index := range b {
+		if !containsIndex(res, index) {
+			res = append(res, index)
+		}
+	}
+	return res
+}
+
+func containsIndex(indices []uint32, index uint32) bool {
+	for _, i := range indices {
+		if i == index {
+			return true
+		}
+	}
+	return false
+}
+
+func Test_Reshare(t *testing.T) {
+	priv, pub := bls.GenerateRandomKey()
+	shares, commitments, err := threshold.Split(priv, 3, 5)
+	require.NoError(t, err)
+
+	// (3, 5) -> (4, 7) with #2 having lost its share
+	newParticipants := []uint32{3, 4, 5, 6, 7, 8, 9}
+	nodes := newReshareNodes(t, shares, commitments, newParticipants, 4, 2)
+	results := runDKG(t, nodes, nil, nil, nil)
+
+	reshared := newResults(nodes, results, newParticipants)
+	require.Len(t, reshared, 7)
+	requireAgreement(t, reshared, []uint32{1, 3, 4, 5})
+	require.Equal(t, pub.Marshal(), reshared[0].PublicKey().Marshal())
+	require.Equal(t, 4, reshared[0].Commitments.Threshold())
+	require.Nil(t, results[0])
+
+	// The new shares recover the same key, but 3 of them are not enough
+	newShares := make([]threshold.Share, len(reshared))
+	for i, result := range reshared {
+		newShares[i] = result.Share
+	}
+	recovered, err := threshold.Recover(newShares[3:])
+	require.NoError(t, err)
+	require.Equal(t, priv.Scalar(), recovered.Scalar())
+	recovered, err = threshold.Recover(newShares[4:])
+	require.NoError(t, err)
+	require.NotEqual(t, priv.Scalar(), recovered.Scalar())
+}
+
+func Test_ReshareDisqualifiesDealers(t *testing.T) {
+	priv, pub := bls.GenerateRandomKey()
+	shares, commitments, err := threshold.Split(priv, 2, 4)
+	require.NoError(t, err)
+
+	// #1 deals another secret, so its commitments do not match its old share
+	other, _ := bls.GenerateRandomKey()
+	otherPoly, err := threshold.NewPolynomial(other, 3)
+	require.NoError(t, err)
+	invalid := func(broadcast *dkg.Broadcast) bool {
+		if broadcast.Dealer == 1 {
+			broadcast.Commitments = otherPoly.Commitments()
+		}
+		return true
+	}
+	corrupt := func(deal *dkg.Deal) bool {
+		if deal.Dealer == 1 {
+			share, err := otherPoly.Share(deal.Recipient)
+			require.NoError(t, err)
+			deal.Share = share.Key
+		}
+		return true
+	}
+	newParticipants := []uint32{5, 6, 7}
+	nodes := newReshareNodes(t, shares, commitments, newParticipants, 3)
+	reshared := newResults(nodes, runDKG(t, nodes, invalid, corrupt, nil), newParticipants)
+	requireAgreement(t, reshared, []uint32{2, 3, 4})
+	require.Equal(t, pub.Marshal(), reshared[0].PublicKey().Marshal())
+
+	// Only one of the old threshold 2 is left
+	nodes = newReshareNodes(t, shares, commitments, newParticipants, 3, 2, 3, 4)
+	for _, dealer := range nodes {
+		if broadcast := dealer.Broadcast(); broadcast != nil {
+			require.NoError(t, nodes[4].ProcessBroadcast(*broadcast))
+		}
+		deals, err := dealer.Deals()
+		require.NoError(t, err)
+		for _, deal := range deals {
+			if deal.Recipient == nodes[4].Index() {
+				require.NoError(t, nodes[4].ProcessDeal(deal))
+			}
+		}
+	}
+	_, err = nodes[4].Finalize()
+	require.Equal(t, dkg.ErrNotEnoughQualified, err)
+}
+
+func Test_Refresh(t *testing.T) {
+	priv, pub := bls.GenerateRandomKey()
+	shares, commitments, err := threshold.Split(priv, 3, 4)
+	require.NoError(t, err)
+	participants := []uint32{1, 2, 3, 4}
+
+	nodes := make([]*dkg.Node, len(shares))
+	for i, share := range shares {
+		nodes[i], err = dkg.NewRefreshNode(share, participants, commitments)
+		require.NoError(t, err)
+	}
+	results := runDKG(t, nodes, nil, nil, nil)
+	requireAgreement(t, results, participants)
+	require.Equal(t, pub.Marshal(), results[0].PublicKey().Marshal())
+
+	// The shares are re-randomized: the old ones do not mix with the new ones
+	for i, result := range results {
+		require.Equal(t, shares[i].Index, result.Share.Index)
+		require.NotEqual(t, shares[i].Key.Scalar(), result.Share.Key.Scalar())
+	}
+	mixed, err := threshold.Recover([]threshold.Share{shares[0], shares[1], results[2].Share})
+	require.NoError(t, err)
+	require.NotEqual(t, priv.Scalar(), mixed.Scalar())
+
+	// A share must match the commitments
+	_, err = dkg.NewRefreshNode(threshold.Share{Index: 1, Key: shares[1].Key}, participants, commitments)
+	require.Equal(t, dkg.ErrInvalidShare, err)
+	_, err = dkg.NewRefreshNode(threshold.Share{Index: 9, Key: shares[1].Key}, participants, commitments)
+	require.Equal(t, dkg.ErrInvalidConfig, err)
+}
@@ -0,0 +1,103 @@
+package test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/eywa-protocol/bls-crypto/bls"
+	"github.com/eywa-protocol/bls-crypto/setup"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SetupMembershipKeys(t *testing.T) {
+	const total = 5
+	privs, pubs := GenerateRandomKeys(total)
+	group, err := bls.NewGroup(pubs)
+	require.NoError(t, err)
+	collectors := make([]*setup.Collector, total)
+	for i := range collectors {
+		collectors[i], err = setup.NewCollector(uint32(i), group)
+		require.NoError(t, err)
+	}
+
+	// Every member sends its parts to every member
+	for _, priv := range privs {
+		parts, err := setup.GenerateParts(priv, group)
+		require.NoError(t, err)
+		require.Len(t, parts, total)
+		for _, part := range parts {
+			require.False(t, collectors[part.Recipient].Done())
+			require.NoError(t, collectors[part.Recipient].Add(part))
+		}
+	}
+
+	coefs := bls.CalculateAntiRogueCoefficients(pubs)
+	allPub := bls.AggregatePublicKeys(pubs, coefs)
+	expected := AggregateMembershipKeys(privs, pubs, allPub, coefs)
+	mks := make([]bls.Signature, total)
+	for i, collector := range collectors {
+		require.True(t, collector.Done())
+		require.Empty(t, collector.Missing())
+		require.Empty(t, collector.Blamed())
+		require.Equal(t, allPub.Marshal(), collector.AggregatedPublicKey().Marshal())
+		var err error
+		mks[i], err = collector.MembershipKey()
+		require.NoError(t, err)
+		require.Equal(t, expected[i].Marshal(), mks[i].Marshal())
+	}
+
+	// The membership keys sign multisignatures
+	mask := big.NewInt(0b10110)
+	sig := privs[1].Multisign(msg, allPub, mks[1]).
+		Aggregate(privs[2].Multisign(msg, allPub, mks[2])).
+		Aggregate(privs[4].Multisign(msg, allPub, mks[4]))
+	multi := bls.Multisig{PartSignature: sig, PartPublicKey: pubs[1].Aggregate(pubs[2]).Aggregate(pubs[4]), PartMask: mask}
+	require.True(t, multi.Verify(allPub, msg))
+}
+
+func Test_SetupBlamesInvalidParts(t *testing.T) {
+	privs, pubs := GenerateRandomKeys(4)
+	group, err := bls.NewGroup(pubs)
+	require.NoError(t, err)
+	collector, err := setup.NewCollector(2, group)
+	require.NoError(t, err)
+
+	for i, priv := range privs {
+		parts, err := setup.GenerateParts(priv, group)
+		require.NoError(t, err)
+		part := parts[2]
+		switch i {
+		case 1:
+			// the part for another member
+			part.Signature = parts[3].Signature
+		case 3:
+			// the part signed by another key
+			part.Signature = privs[0].GenerateMembershipKeyPart(2, collector.AggregatedPublicKey(), group.AntiRogueCoefficients()[3])
+		}
+		err = collector.Add(part)
+		if i == 1 || i == 3 {
+			require.True(t, errors.Is(err, setup.ErrInvalidPart))
+		} else {
+			require.NoError(t, err)
+		}
+	}
+	require.False(t, collector.Done())
+	require.Equal(t, []uint32{1, 3}, collector.Blamed())
+	require.Equal(t, []uint32{1, 3}, collector.Missing())
+	_, err = collector.MembershipKey()
+	require.Equal(t, setup.ErrIncomplete, err)
+
+	parts, err := setup.GenerateParts(privs[0], group)
+	require.NoError(t, err)
+	require.Equal(t, setup.ErrDuplicatePart, collector.Add(parts[2]))
+	require.Equal(t, setup.ErrWrongRecipient, collector.Add(parts[1]))
+	require.Equal(t, setup.ErrUnknownMember, collector.Add(setup.Part{Sender: 4, Recipient: 2}))
+	require.True(t, errors.Is(collector.Add(setup.Part{Sender: 1, Recipient: 2}), setup.ErrInvalidPart))
+
+	_, err = setup.NewCollector(4, group)
+	require.Equal(t, setup.ErrUnknownMember, err)
+	outsider, _ := bls.GenerateRandomKey()
+	_, err = setup.GenerateParts(outsider, group)
+	require.Equal(t, setup.ErrUnknownMember, err)
+}
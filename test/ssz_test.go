@@ -0,0 +1,305 @@
+package test
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/eywa-protocol/bls-crypto/bls"
+	"github.com/stretchr/testify/require"
+)
+
+// sha256Concat hashes the concatenation of the chunks
+func sha256Concat(chunks ...[]byte) []byte {
+	hasher := sha256.New()
+	for _, chunk := range chunks {
+		hasher.Write(chunk)
+	}
+	return hasher.Sum(nil)
+}
+
+// zeroRoot returns the root of the Merkle tree of the depth with zero chunks
+func zeroRoot(depth int) []byte {
+	res := make([]byte, 32)
+	for i := 0; i < depth; i++ {
+		res = sha256Concat(res, res)
+	}
+	return res
+}
+
+func lengthChunk(length uint64) []byte {
+	res := make([]byte, 32)
+	binary.LittleEndian.PutUint64(res, length)
+	return res
+}
+
+func Test_SSZBitlistVectors(t *testing.T) {
+	priv, err := bls.ReadPrivateKey(testPrivateKey)
+	require.NoError(t, err)
+	sig := priv.Sign(msg)
+	pub := priv.PublicKey()
+	prefix := append(append([]byte{}, sig.MarshalCompressed()...), pub.MarshalCompressed()...)
+	prefix = append(prefix, 100, 0, 0, 0) // offset of the bitlist
+
+	vectors := []struct {
+		mask    *big.Int
+		bitlist string
+	}{
+		{big.NewInt(0), "01"},
+		{big.NewInt(0b1), "03"},
+		{big.NewInt(0b101), "0d"},
+		{big.NewInt(0xff), "ff01"},
+		{big.NewInt(0x1ff), "ff03"},
+		{big.NewInt(0x8001), "018001"},
+	}
+	for _, v := range vectors {
+		multi := bls.Multisig{PartSignature: sig, PartPublicKey: pub, PartMask: v.mask}
+		data, err := multi.MarshalSSZ()
+		require.NoError(t, err)
+		require.Equal(t, v.bitlist, hex.EncodeToString(data[len(prefix):]), v.bitlist)
+		require.Equal(t, prefix, data[:len(prefix)])
+		require.Equal(t, len(data), multi.SizeSSZ())
+
+		var decoded bls.Multisig
+		require.NoError(t, decoded.UnmarshalSSZ(data))
+		require.Equal(t, 0, v.mask.Cmp(decoded.PartMask))
+		require.Equal(t, sig.Marshal(), decoded.PartSignature.Marshal())
+		require.Equal(t, pub.Marshal(), decoded.PartPublicKey.Marshal())
+	}
+}
+
+func Test_SSZBitlistWithSize(t *testing.T) {
+	// The bitlist has the length of the group, as the aggregation bits of
+	// consensus clients have the length of the committee. E.g. the bits
+	// [1, 1, 0, 1, 0, 1, 0, 0] of the committee of 8 serialize to 0x2b01.
+	multi := bls.Multisig{PartSignature: privs[0].Sign(msg), PartPublicKey: pubs[0], PartMask: big.NewInt(0b101011)}
+	data, err := multi.MarshalSSZWithSize(8)
+	require.NoError(t, err)
+	require.Equal(t, "2b01", hex.EncodeToString(data[100:]))
+
+	var decoded bls.Multisig
+	require.NoError(t, decoded.UnmarshalSSZWithSize(data, 8))
+	require.Equal(t, big.NewInt(0b101011), decoded.PartMask)
+	require.Equal(t, bls.ErrIndexRange, decoded.UnmarshalSSZWithSize(data, 9))
+	require.Equal(t, bls.ErrNonCanonical, decoded.UnmarshalSSZ(data))
+	expected, err := multi.HashTreeRootWithSize(8)
+	require.NoError(t, err)
+	root, err := decoded.HashTreeRootWithSize(8)
+	require.NoError(t, err)
+	require.Equal(t, expected, root)
+
+	// Trailing zero bits are kept within the size
+	for _, v := range []struct {
+		bitlist string
+		mask    int64
+		size    int
+	}{
+		{"0b01", 0b1011, 8},
+		{"2b", 0b1011, 5},
+		{"0102", 0b1, 9},
+	} {
+		raw := append(append([]byte{}, data[:100]...), mustDecodeHex(t, v.bitlist)...)
+		require.NoError(t, decoded.UnmarshalSSZWithSize(raw, v.size), v.bitlist)
+		require.Equal(t, big.NewInt(v.mask), decoded.PartMask, v.bitlist)
+		encoded, err := decoded.MarshalSSZWithSize(v.size)
+		require.NoError(t, err)
+		require.Equal(t, raw, encoded, v.bitlist)
+	}
+
+	// The multisignature of the builder has the size of the group
+	builder, err := bls.NewMultisigBuilder(aggPub, pubs, msg)
+	require.NoError(t, err)
+	require.NoError(t, builder.Add(2, privs[2].Multisign(msg, aggPub, mks[2])))
+	built, err := builder.Build()
+	require.NoError(t, err)
+	data, err = built.MarshalSSZWithSize(len(pubs))
+	require.NoError(t, err)
+	require.Equal(t, "040000000000000001", hex.EncodeToString(data[100:]))
+
+	// The size is committed to by the root
+	chunk := make([]byte, 32)
+	chunk[0] = 0b100
+	bitsRoot := sha256Concat(sha256Concat(sha256Concat(chunk, zeroRoot(0)), zeroRoot(1)), zeroRoot(2))
+	sigRoot, err := built.PartSignature.HashTreeRoot()
+	require.NoError(t, err)
+	pubRoot, err := built.PartPublicKey.HashTreeRoot()
+	require.NoError(t, err)
+	signersRoot := sha256Concat(bitsRoot, lengthChunk(uint64(len(pubs))))
+	root, err = built.HashTreeRootWithSize(len(pubs))
+	require.NoError(t, err)
+	require.Equal(t, sha256Concat(sha256Concat(sigRoot[:], pubRoot[:]), sha256Concat(signersRoot, zeroRoot(0))), root[:])
+	other, err := built.HashTreeRoot()
+	require.NoError(t, err)
+	require.NotEqual(t, root, other)
+
+	// A signer out of the group
+	_, err = built.HashTreeRootWithSize(2)
+	require.Equal(t, bls.ErrIndexRange, err)
+	_, err = built.MarshalSSZWithSize(bls.MaxGroupSize + 1)
+	require.Equal(t, bls.ErrIndexRange, err)
+}
+
+func Test_SSZZeroHashes(t *testing.T) {
+	// The roots of zero subtrees, as in zero_hashes of the deposit contract
+	for depth, expected := range []string{
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"f5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a92759fb4b",
+		"db56114e00fdd4c1f85c892bf35ac9a89289aaecb1ebd0a96cde606a748b5d71",
+		"c78009fdf07fc56a11f122370658a353aaa542ed63e44c4bc15ff4cd105ab33c",
+	} {
+		require.Equal(t, expected, hex.EncodeToString(zeroRoot(depth)))
+	}
+
+	// The empty signers bitlist is the root of 8 zero chunks mixed in with 0
+	multi := bls.Multisig{PartSignature: privs[0].Sign(msg), PartPublicKey: pubs[0], PartMask: big.NewInt(0)}
+	sigRoot, err := multi.PartSignature.HashTreeRoot()
+	require.NoError(t, err)
+	pubRoot, err := multi.PartPublicKey.HashTreeRoot()
+	require.NoError(t, err)
+	signersRoot := mustDecodeHex(t, "c78009fdf07fc56a11f122370658a353aaa542ed63e44c4bc15ff4cd105ab33c")
+	signersRoot = sha256Concat(signersRoot, make([]byte, 32))
+	root, err := multi.HashTreeRoot()
+	require.NoError(t, err)
+	require.Equal(t, sha256Concat(sha256Concat(sigRoot[:], pubRoot[:]), sha256Concat(signersRoot, make([]byte, 32))), root[:])
+}
+
+func Test_SSZHashTreeRoot(t *testing.T) {
+	priv, err := bls.ReadPrivateKey(testPrivateKey)
+	require.NoError(t, err)
+	sig := priv.Sign(msg)
+	pub := priv.PublicKey()
+
+	// Bytes32 is its own root, Bytes64 is the hash of its two chunks
+	sigRoot, err := sig.HashTreeRoot()
+	require.NoError(t, err)
+	require.Equal(t, sig.MarshalCompressed(), sigRoot[:])
+	pubRoot, err := pub.HashTreeRoot()
+	require.NoError(t, err)
+	compressed := pub.MarshalCompressed()
+	require.Equal(t, sha256Concat(compressed[:32], compressed[32:]), pubRoot[:])
+
+	// Bitlist[2048] has 8 chunks: 0b101 is packed into the first one
+	multi := bls.Multisig{PartSignature: sig, PartPublicKey: pub, PartMask: big.NewInt(0b101)}
+	chunk := make([]byte, 32)
+	chunk[0] = 0b101
+	bitsRoot := sha256Concat(sha256Concat(sha256Concat(chunk, zeroRoot(0)), zeroRoot(1)), zeroRoot(2))
+	signersRoot := sha256Concat(bitsRoot, lengthChunk(3))
+	expected := sha256Concat(sha256Concat(sigRoot[:], pubRoot[:]), sha256Concat(signersRoot, zeroRoot(0)))
+	root, err := multi.HashTreeRoot()
+	require.NoError(t, err)
+	require.Equal(t, expected, root[:])
+
+	// The empty bitlist
+	multi.PartMask = big.NewInt(0)
+	signersRoot = sha256Concat(zeroRoot(3), lengthChunk(0))
+	expected = sha256Concat(sha256Concat(sigRoot[:], pubRoot[:]), sha256Concat(signersRoot, zeroRoot(0)))
+	root, err = multi.HashTreeRoot()
+	require.NoError(t, err)
+	require.Equal(t, expected, root[:])
+
+	// List[Bytes64, 2048] of two members has the depth of 11
+	group, err := bls.NewGroup([]bls.PublicKey{pub, pubs[0]})
+	require.NoError(t, err)
+	root0, err := pubs[0].HashTreeRoot()
+	require.NoError(t, err)
+	listRoot := sha256Concat(pubRoot[:], root0[:])
+	for depth := 1; depth < 11; depth++ {
+		listRoot = sha256Concat(listRoot, zeroRoot(depth))
+	}
+	// and the empty ByteList[255] of the legacy suite has the depth of 3
+	root, err = group.HashTreeRoot()
+	require.NoError(t, err)
+	suiteRoot := sha256Concat(zeroRoot(3), lengthChunk(0))
+	require.Equal(t, sha256Concat(sha256Concat(listRoot, lengthChunk(2)), suiteRoot), root[:])
+
+	// The root commits to the signers
+	multi.PartMask = big.NewInt(0b100)
+	other, err := multi.HashTreeRoot()
+	require.NoError(t, err)
+	require.NotEqual(t, expected, other[:])
+}
+
+func Test_SSZGroup(t *testing.T) {
+	group, err := bls.NewGroup(pubs)
+	require.NoError(t, err)
+	data, err := group.MarshalSSZ()
+	require.NoError(t, err)
+	require.Len(t, data, 8+len(pubs)*bls.CompressedPublicKeySize)
+	require.Equal(t, len(data), group.SizeSSZ())
+	require.Equal(t, "08000000", hex.EncodeToString(data[:4]))
+	require.Equal(t, uint32(len(data)), binary.LittleEndian.Uint32(data[4:]))
+
+	var decoded bls.Group
+	require.NoError(t, decoded.UnmarshalSSZ(data))
+	require.Equal(t, group.ID(), decoded.ID())
+	require.Equal(t, bls.LegacyMultisigSuite, decoded.Suite())
+	expected, err := group.HashTreeRoot()
+	require.NoError(t, err)
+	root, err := decoded.HashTreeRoot()
+	require.NoError(t, err)
+	require.Equal(t, expected, root)
+
+	// The suite is kept and committed to by the root
+	separated := group.WithSuite(bls.SeparatedMultisigSuite)
+	tagged, err := separated.MarshalSSZ()
+	require.NoError(t, err)
+	require.Equal(t, len(tagged), separated.SizeSSZ())
+	require.Equal(t, bls.SeparatedMultisigSuite.Tag(), string(tagged[len(data):]))
+	require.NoError(t, decoded.UnmarshalSSZ(tagged))
+	require.Equal(t, bls.SeparatedMultisigSuite, decoded.Suite())
+	other, err := decoded.HashTreeRoot()
+	require.NoError(t, err)
+	require.NotEqual(t, expected, other)
+
+	members := append([]byte{8, 0, 0, 0, 72, 0, 0, 0}, data[8:72]...)
+	require.Equal(t, bls.ErrInvalidLength, decoded.UnmarshalSSZ(data[:7]))
+	require.Equal(t, bls.ErrNonCanonical, decoded.UnmarshalSSZ(data[:len(data)-1]))
+	require.Equal(t, bls.ErrInvalidLength, decoded.UnmarshalSSZ(append([]byte{8, 0, 0, 0, 71, 0, 0, 0}, data[8:72]...)))
+	require.Equal(t, bls.ErrNoKeys, decoded.UnmarshalSSZ([]byte{8, 0, 0, 0, 8, 0, 0, 0}))
+	require.Equal(t, bls.ErrDuplicateMember, decoded.UnmarshalSSZ(append([]byte{8, 0, 0, 0, 136, 0, 0, 0}, append(members[8:], members[8:]...)...)))
+	require.NoError(t, decoded.UnmarshalSSZ(members))
+}
+
+func Test_SSZMalformed(t *testing.T) {
+	multi := bls.Multisig{PartSignature: privs[0].Sign(msg), PartPublicKey: pubs[0], PartMask: big.NewInt(0b1011)}
+	data, err := multi.MarshalSSZ()
+	require.NoError(t, err)
+	withTail := func(tail ...byte) []byte {
+		return append(append([]byte{}, data[:100]...), tail...)
+	}
+	modified := func(offset int, b byte) []byte {
+		res := append([]byte{}, data...)
+		res[offset] = b
+		return res
+	}
+
+	cases := []struct {
+		data []byte
+		err  error
+	}{
+		{nil, bls.ErrInvalidLength},
+		{data[:99], bls.ErrInvalidLength},
+		{withTail(), bls.ErrInvalidLength},
+		{withTail(0x0b, 0x00), bls.ErrInvalidLength}, // no delimiting bit
+		{withTail(0x0b, 0x01), bls.ErrNonCanonical},  // trailing zero bits
+		{withTail(0x2b), bls.ErrNonCanonical},        // [1, 1, 0, 1, 0]
+		{modified(96, 101), bls.ErrNonCanonical},     // offset
+		{modified(0, data[0]&0x3f), bls.ErrNonCanonical},
+	}
+	for i, c := range cases {
+		var decoded bls.Multisig
+		require.Equal(t, c.err, decoded.UnmarshalSSZ(c.data), "case %d", i)
+	}
+
+	_, err = bls.Multisig{PartSignature: multi.PartSignature, PartPublicKey: pubs[0]}.MarshalSSZ()
+	require.Equal(t, bls.ErrInvalidMask, err)
+	_, err = bls.Multisig{PartPublicKey: pubs[0], PartMask: big.NewInt(1)}.HashTreeRoot()
+	require.Equal(t, bls.ErrNilSignature, err)
+	tooMany := new(big.Int).SetBit(new(big.Int), bls.MaxGroupSize, 1)
+	_, err = bls.Multisig{PartSignature: multi.PartSignature, PartPublicKey: pubs[0], PartMask: tooMany}.MarshalSSZ()
+	require.Equal(t, bls.ErrIndexRange, err)
+	_, err = bls.PublicKey{}.MarshalSSZ()
+	require.Equal(t, bls.ErrNilKey, err)
+}
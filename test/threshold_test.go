@@ -0,0 +1,135 @@
+package test
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/eywa-protocol/bls-crypto/bls"
+	"github.com/eywa-protocol/bls-crypto/threshold"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_ThresholdSignature(t *testing.T) {
+	priv, pub := bls.GenerateRandomKey()
+	shares, commitments, err := threshold.Split(priv, 3, 5)
+	require.NoError(t, err)
+	require.Len(t, shares, 5)
+	require.Equal(t, 3, commitments.Threshold())
+	require.Equal(t, pub.Marshal(), commitments.PublicKey().Marshal())
+
+	partials := make([]bls.Signature, len(shares))
+	for i, share := range shares {
+		require.True(t, commitments.VerifyShare(share))
+		partials[i] = threshold.PartialSign(share, msg)
+		require.True(t, threshold.VerifyPartial(commitments, share.Index, msg, partials[i]))
+		require.False(t, threshold.VerifyPartial(commitments, share.Index%5+1, msg, partials[i]))
+	}
+
+	// Any 3 of 5 partial signatures combine into the signature of the group key
+	for _, subset := range [][]int{{0, 1, 2}, {4, 2, 0}, {1, 3, 4}, {0, 1, 2, 3, 4}} {
+		sigs := make([]bls.Signature, len(subset))
+		indices := make([]uint32, len(subset))
+		for i, j := range subset {
+			sigs[i], indices[i] = partials[j], shares[j].Index
+		}
+		sig, err := threshold.CombineSignatures(sigs, indices)
+		require.NoError(t, err)
+		require.Equal(t, priv.Sign(msg).Marshal(), sig.Marshal())
+		require.True(t, sig.Verify(pub, msg))
+	}
+
+	// 2 of 5 are not enough
+	sig, err := threshold.CombineSignatures(partials[:2], []uint32{1, 2})
+	require.NoError(t, err)
+	require.False(t, sig.Verify(pub, msg))
+
+	// The combined signature verifies in EVM
+	sig, err = threshold.CombineSignatures(partials[2:], []uint32{3, 4, 5})
+	require.NoError(t, err)
+	_, err = blsSignatureTest.VerifySignature(owner, pub.Marshal(), msg, sig.Marshal())
+	require.NoError(t, err)
+	backend.Commit()
+	verifiedSol, err := blsSignatureTest.Verified(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.True(t, verifiedSol)
+}
+
+func Test_ThresholdRecover(t *testing.T) {
+	priv, _ := bls.GenerateRandomKey()
+	shares, commitments, err := threshold.Split(priv, 4, 7)
+	require.NoError(t, err)
+
+	recovered, err := threshold.Recover(shares[3:])
+	require.NoError(t, err)
+	require.Equal(t, priv.Scalar(), recovered.Scalar())
+
+	recovered, err = threshold.Recover(shares[4:])
+	require.NoError(t, err)
+	require.NotEqual(t, priv.Scalar(), recovered.Scalar())
+
+	// A share of another polynomial fails the commitments
+	otherShares, _, err := threshold.Split(priv, 4, 7)
+	require.NoError(t, err)
+	require.False(t, commitments.VerifyShare(otherShares[0]))
+	require.False(t, commitments.VerifyShare(threshold.Share{Index: 1}))
+}
+
+func Test_ThresholdErrors(t *testing.T) {
+	priv, _ := bls.GenerateRandomKey()
+	_, _, err := threshold.Split(priv, 0, 3)
+	require.Equal(t, threshold.ErrThreshold, err)
+	_, _, err = threshold.Split(priv, 4, 3)
+	require.Equal(t, threshold.ErrThreshold, err)
+
+	_, err = threshold.LagrangeCoefficients(nil)
+	require.Equal(t, threshold.ErrNoShares, err)
+	_, err = threshold.LagrangeCoefficients([]uint32{1, 0})
+	require.Equal(t, threshold.ErrInvalidIndex, err)
+	_, err = threshold.LagrangeCoefficients([]uint32{1, 2, 1})
+	require.Equal(t, threshold.ErrDuplicateIndex, err)
+
+	sig := priv.Sign(msg)
+	_, err = threshold.CombineSignatures([]bls.Signature{sig}, []uint32{1, 2})
+	require.Equal(t, bls.ErrLengthMismatch, err)
+	_, err = threshold.CombineSignatures([]bls.Signature{sig, {}}, []uint32{1, 2})
+	require.Equal(t, bls.ErrNilSignature, err)
+
+	_, err = threshold.NewPolynomial(bls.PrivateKey{}, 2)
+	require.Equal(t, threshold.ErrInvalidSecret, err)
+	zero, err := bls.UnmarshalPrivateKey([]byte("0"))
+	require.NoError(t, err)
+	_, _, err = threshold.Split(zero, 2, 3)
+	require.Equal(t, threshold.ErrInvalidSecret, err)
+
+	_, commitments, err := threshold.Split(priv, 2, 3)
+	require.NoError(t, err)
+	_, other, err := threshold.Split(priv, 3, 3)
+	require.NoError(t, err)
+	_, err = commitments.Aggregate(other)
+	require.Equal(t, threshold.ErrInvalidCommitments, err)
+	_, err = threshold.Commitments{}.Aggregate(threshold.Commitments{})
+	require.Equal(t, threshold.ErrInvalidCommitments, err)
+	_, err = commitments.Aggregate(threshold.Commitments{commitments[0], {}})
+	require.Equal(t, bls.ErrNilKey, err)
+}
+
+func Test_AggregateCommitments(t *testing.T) {
+	priv0, _ := bls.GenerateRandomKey()
+	priv1, _ := bls.GenerateRandomKey()
+	shares0, commitments0, err := threshold.Split(priv0, 2, 3)
+	require.NoError(t, err)
+	shares1, commitments1, err := threshold.Split(priv1, 2, 3)
+	require.NoError(t, err)
+
+	commitments, err := commitments0.Aggregate(commitments1)
+	require.NoError(t, err)
+	require.Equal(t, 2, commitments.Threshold())
+	require.Equal(t, priv0.PublicKey().Aggregate(priv1.PublicKey()).Marshal(), commitments.PublicKey().Marshal())
+	for i := range shares0 {
+		scalar := shares0[i].Key.Scalar()
+		scalar.Add(scalar, shares1[i].Key.Scalar()).Mod(scalar, bls.Order())
+		key, err := bls.PrivateKeyFromScalar(scalar)
+		require.NoError(t, err)
+		require.True(t, commitments.VerifyShare(threshold.Share{Index: shares0[i].Index, Key: key}))
+	}
+}
@@ -28,11 +28,16 @@ func GenerateRandomKeys(total int) ([]bls.PrivateKey, []bls.PublicKey) {
 //
 // MKi = (A1⋅pk1)×H(P, i) + (A2⋅pk2)×H(P, i) + ...
 func AggregateMembershipKeys(privs []bls.PrivateKey, pubs []bls.PublicKey, aggPub bls.PublicKey, coefs []big.Int) []bls.Signature {
+	return AggregateMembershipKeysWith(bls.LegacyMultisigSuite, privs, pubs, aggPub, coefs)
+}
+
+// AggregateMembershipKeysWith prepares the membership keys hashed by the suite
+func AggregateMembershipKeysWith(suite bls.MultisigSuite, privs []bls.PrivateKey, pubs []bls.PublicKey, aggPub bls.PublicKey, coefs []big.Int) []bls.Signature {
 	res := make([]bls.Signature, len(pubs))
 	for i := 0; i < len(pubs); i++ {
 		res[i] = bls.ZeroSignature()
 		for j := 0; j < len(pubs); j++ {
-			res[i] = res[i].Aggregate(privs[j].GenerateMembershipKeyPart(byte(i), aggPub, coefs[j]))
+			res[i] = res[i].Aggregate(suite.GenerateMembershipKeyPart(privs[j], uint32(i), aggPub, coefs[j]))
 		}
 	}
 	return res
@@ -0,0 +1,147 @@
+package test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/eywa-protocol/bls-crypto/bls"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_WeightedMultisig(t *testing.T) {
+	privs, pubs := GenerateRandomKeys(5)
+	weights := []*big.Int{big.NewInt(40), big.NewInt(25), big.NewInt(15), big.NewInt(10), big.NewInt(10)}
+	group, err := bls.NewWeightedGroup(pubs, weights, big.NewInt(67))
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(100), group.TotalWeight())
+
+	allPub := group.AggregatedPublicKey()
+	require.Equal(t, bls.AggregatePublicKeys(pubs, bls.CalculateAntiRogueCoefficients(pubs)).Marshal(), allPub.Marshal())
+	mks := AggregateMembershipKeys(privs, pubs, allPub, group.AntiRogueCoefficients())
+
+	multisign := func(signers ...uint32) bls.Multisig {
+		builder, err := group.Group().NewMultisigBuilder(msg)
+		require.NoError(t, err)
+		for _, i := range signers {
+			require.NoError(t, builder.Add(i, privs[i].Multisign(msg, allPub, mks[i])))
+		}
+		multi, err := builder.Build()
+		require.NoError(t, err)
+		return multi
+	}
+
+	// 40 + 15 + 10 + 10 = 75 of 100 signed
+	heavy := multisign(0, 2, 3, 4)
+	weight, err := group.SignedWeight(heavy.PartMask)
+	require.NoError(t, err)
+	require.Equal(t, big.NewInt(75), weight)
+	require.NoError(t, group.Verify(heavy, msg))
+	require.Equal(t, bls.ErrInvalidSignature, group.Verify(heavy, GenRandomBytes(MESSAGE_SIZE)))
+
+	// 4 of 5 members, but 25 + 15 + 10 + 10 = 60 of 100 signed
+	light := multisign(1, 2, 3, 4)
+	require.True(t, light.Verify(allPub, msg))
+	require.Equal(t, bls.ErrInsufficientWeight, group.Verify(light, msg))
+
+	// The group keeps its own copy of the weights
+	weights[1].SetInt64(100)
+	weights[0] = big.NewInt(0)
+	require.Equal(t, big.NewInt(100), group.TotalWeight())
+	require.Equal(t, bls.ErrInsufficientWeight, group.Verify(light, msg))
+	group.Calldata(heavy, msg).Weights[0].SetInt64(0)
+	require.NoError(t, group.Verify(heavy, msg))
+
+	_, err = group.SignedWeight(big.NewInt(1 << 5))
+	require.Equal(t, bls.ErrIndexRange, err)
+	_, err = group.SignedWeight(nil)
+	require.Equal(t, bls.ErrInvalidMask, err)
+
+	// The commitment binds the weights and the threshold to the group
+	commitment := group.WeightsCommitment()
+	data := allPub.Marshal()
+	for _, weight := range []int64{40, 25, 15, 10, 10, 67} {
+		data = append(data, common.LeftPadBytes(big.NewInt(weight).Bytes(), 32)...)
+	}
+	require.Equal(t, crypto.Keccak256(data), commitment[:])
+	_, err = blsSignatureTest.SetWeightsHash(owner, commitment)
+	require.NoError(t, err)
+	backend.Commit()
+
+	// Verify in EVM
+	lowered := group.Calldata(light, msg)
+	lowered.Threshold = big.NewInt(60)
+	raised := group.Calldata(light, msg)
+	raised.Weights[1] = big.NewInt(40)
+	for _, test := range []struct {
+		calldata bls.WeightedCalldata
+		verified bool
+	}{
+		{group.Calldata(heavy, msg), true},
+		{group.Calldata(light, msg), false},
+		// the weights and the threshold are not those committed to
+		{lowered, false},
+		{raised, false},
+	} {
+		calldata := test.calldata
+		_, err = blsSignatureTest.VerifyWeightedMultisignature(owner, calldata.AggregatedPublicKey, calldata.PartPublicKey,
+			calldata.Message, calldata.PartSignature, calldata.SignersBitmask, calldata.Weights, calldata.Threshold)
+		require.NoError(t, err)
+		backend.Commit()
+		verifiedSol, err := blsSignatureTest.Verified(&bind.CallOpts{})
+		require.NoError(t, err)
+		require.Equal(t, test.verified, verifiedSol)
+	}
+
+	// The signed weight wrapping modulo 2^256 to 67 is rejected
+	overflow := group.Calldata(light, msg)
+	overflow.Weights = []*big.Int{big.NewInt(0), math.MaxBig256, big.NewInt(68), big.NewInt(0), big.NewInt(0)}
+	data = allPub.Marshal()
+	for _, weight := range append(overflow.Weights, overflow.Threshold) {
+		data = append(data, math.U256Bytes(new(big.Int).Set(weight))...)
+	}
+	var overflowCommitment [32]byte
+	copy(overflowCommitment[:], crypto.Keccak256(data))
+	_, err = blsSignatureTest.SetWeightsHash(owner, overflowCommitment)
+	require.NoError(t, err)
+	backend.Commit()
+	_, err = blsSignatureTest.VerifyWeightedMultisignature(owner, overflow.AggregatedPublicKey, overflow.PartPublicKey,
+		overflow.Message, overflow.PartSignature, overflow.SignersBitmask, overflow.Weights, overflow.Threshold)
+	require.NoError(t, err)
+	backend.Commit()
+	verifiedSol, err := blsSignatureTest.Verified(&bind.CallOpts{})
+	require.NoError(t, err)
+	require.False(t, verifiedSol)
+
+	packed, err := group.Calldata(heavy, msg).Pack()
+	require.NoError(t, err)
+	selector := crypto.Keccak256([]byte("verifyWeightedMultisignature(bytes,bytes,bytes,bytes,uint256[],uint256[],uint256)"))[:4]
+	require.Equal(t, selector, packed[:4])
+	require.Equal(t, big.NewInt(67), new(big.Int).SetBytes(packed[4+6*32:4+7*32]))
+}
+
+func Test_WeightedGroupErrors(t *testing.T) {
+	_, pubs := GenerateRandomKeys(2)
+	_, err := bls.NewWeightedGroup(pubs, []*big.Int{big.NewInt(1)}, big.NewInt(1))
+	require.Equal(t, bls.ErrLengthMismatch, err)
+	_, err = bls.NewWeightedGroup(pubs, []*big.Int{big.NewInt(1), big.NewInt(-1)}, big.NewInt(1))
+	require.Equal(t, bls.ErrInvalidWeight, err)
+	_, err = bls.NewWeightedGroup(pubs, []*big.Int{big.NewInt(1), nil}, big.NewInt(1))
+	require.Equal(t, bls.ErrInvalidWeight, err)
+	_, err = bls.NewWeightedGroup(pubs, []*big.Int{big.NewInt(1), big.NewInt(1)}, nil)
+	require.Equal(t, bls.ErrInvalidWeight, err)
+	_, err = bls.NewWeightedGroup(nil, nil, big.NewInt(1))
+	require.Equal(t, bls.ErrNoKeys, err)
+
+	// The total weight would overflow uint256 on-chain
+	half := new(big.Int).Lsh(big.NewInt(1), 255)
+	_, err = bls.NewWeightedGroup(pubs, []*big.Int{half, half}, big.NewInt(1))
+	require.Equal(t, bls.ErrInvalidWeight, err)
+	_, err = bls.NewWeightedGroup(pubs, []*big.Int{big.NewInt(1), big.NewInt(1)}, new(big.Int).Lsh(half, 1))
+	require.Equal(t, bls.ErrInvalidWeight, err)
+	_, err = bls.NewWeightedGroup(pubs, []*big.Int{half, new(big.Int).Sub(half, big.NewInt(1))}, half)
+	require.NoError(t, err)
+}
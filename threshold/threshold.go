@@ -0,0 +1,270 @@
+// Package threshold implements t-of-n threshold BLS signatures: a private
+// key is split into n shares by Shamir's secret sharing with Feldman
+// commitments, and any t partial signatures of a message combine into the
+// signature under the single group public key by Lagrange interpolation.
+package threshold
+
+import (
+	"bytes"
+	"crypto/rand"
+	"errors"
+	"math/big"
+
+	"github.com/eywa-protocol/bls-crypto/bls"
+)
+
+var (
+	ErrThreshold          = errors.New("threshold: threshold must be in range 1..total")
+	ErrInvalidIndex       = errors.New("threshold: share index must be non-zero")
+	ErrDuplicateIndex     = errors.New("threshold: duplicate share index")
+	ErrNoShares           = errors.New("threshold: no shares")
+	ErrInvalidSecret      = errors.New("threshold: secret must be in range 1..order-1")
+	ErrInvalidCommitments = errors.New("threshold: commitments must be of the same non-zero threshold")
+)
+
+// order is the order of the groups, the modulus of the scalars
+var order = bls.Order()
+
+// Share is the share of the private key held by the participant with the
+// given index. Indices start from 1: the secret itself is at index 0.
+type Share struct {
+	Index uint32
+	Key   bls.PrivateKey
+}
+
+// PublicKey returns the public key of the share
+func (share Share) PublicKey() bls.PublicKey {
+	return share.Key.PublicKey()
+}
+
+// Polynomial is a secret polynomial over the scalar field, lowest
+// coefficient first. The free coefficient is the shared secret.
+type Polynomial []*big.Int
+
+// NewPolynomial generates a random polynomial of degree threshold-1 sharing
+// the secret, which must be a non-zero private key
+func NewPolynomial(secret bls.PrivateKey, threshold int) (Polynomial, error) {
+	if threshold < 1 {
+		return nil, ErrThreshold
+	}
+	scalar := secret.Scalar()
+	if scalar == nil || scalar.Sign() <= 0 || scalar.Cmp(order) >= 0 {
+		return nil, ErrInvalidSecret
+	}
+	poly := make(Polynomial, threshold)
+	poly[0] = scalar
+	for i := 1; i < threshold; i++ {
+		coef, err := randomScalar()
+		if err != nil {
+			return nil, err
+		}
+		poly[i] = coef
+	}
+	return poly, nil
+}
+
+// Evaluate calculates the value of the polynomial at x
+func (poly Polynomial) Evaluate(x *big.Int) *big.Int {
+	res := new(big.Int)
+	for i := len(poly) - 1; i >= 0; i-- {
+		res.Mul(res, x)
+		res.Add(res, poly[i])
+		res.Mod(res, order)
+	}
+	return res
+}
+
+// Share returns the share of the participant with the given index
+func (poly Polynomial) Share(index uint32) (Share, error) {
+	if index == 0 {
+		return Share{}, ErrInvalidIndex
+	}
+	key, err := bls.PrivateKeyFromScalar(poly.Evaluate(new(big.Int).SetUint64(uint64(index))))
+	if err != nil {
+		return Share{}, err
+	}
+	return Share{Index: index, Key: key}, nil
+}
+
+// Commitments returns the public commitments to the coefficients
+func (poly Polynomial) Commitments() Commitments {
+	res := make(Commitments, len(poly))
+	for i, coef := range poly {
+		key, err := bls.PrivateKeyFromScalar(coef)
+		if err != nil {
+			// a zero coefficient is committed by the point at infinity
+			res[i] = bls.ZeroPublicKey()
+			continue
+		}
+		res[i] = key.PublicKey()
+	}
+	return res
+}
+
+// Commitments are the public keys of the coefficients of the polynomial:
+// Ci = ai×G2. The first one is the group public key.
+type Commitments []bls.PublicKey
+
+// Threshold returns the number of shares needed to sign
+func (commitments Commitments) Threshold() int {
+	return len(commitments)
+}
+
+// PublicKey returns the group public key
+func (commitments Commitments) PublicKey() bls.PublicKey {
+	return commitments[0]
+}
+
+// SharePublicKey calculates the public key of the share with the given
+// index from the commitments:
+//
+// Pi = C0 + i×C1 + i^2×C2 + ...
+func (commitments Commitments) SharePublicKey(index uint32) bls.PublicKey {
+	x := new(big.Int).SetUint64(uint64(index))
+	res := bls.ZeroPublicKey()
+	for i := len(commitments) - 1; i >= 0; i-- {
+		res = res.ScalarMult(x).Aggregate(commitments[i])
+	}
+	return res
+}
+
+// Aggregate adds the commitments to the polynomial of the same degree,
+// committing to the sum of the polynomials
+func (commitments Commitments) Aggregate(onemore Commitments) (Commitments, error) {
+	if len(commitments) == 0 || len(commitments) != len(onemore) {
+		return nil, ErrInvalidCommitments
+	}
+	res := make(Commitments, len(commitments))
+	for i := range res {
+		var err error
+		if res[i], err = commitments[i].AggregateChecked(onemore[i]); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// VerifyShare checks the share against the commitments
+func (commitments Commitments) VerifyShare(share Share) bool {
+	if share.Index == 0 || share.Key.Scalar() == nil {
+		return false
+	}
+	expected := commitments.SharePublicKey(share.Index)
+	return bytes.Equal(share.PublicKey().Marshal(), expected.Marshal())
+}
+
+// Split splits the private key into total shares, any threshold of which
+// can sign on behalf of the key
+func Split(secret bls.PrivateKey, threshold, total int) ([]Share, Commitments, error) {
+	if threshold < 1 || threshold > total {
+		return nil, nil, ErrThreshold
+	}
+	poly, err := NewPolynomial(secret, threshold)
+	if err != nil {
+		return nil, nil, err
+	}
+	shares := make([]Share, total)
+	for i := range shares {
+		if shares[i], err = poly.Share(uint32(i + 1)); err != nil {
+			return nil, nil, err
+		}
+	}
+	return shares, poly.Commitments(), nil
+}
+
+// PartialSign signs the message by the share
+func PartialSign(share Share, message []byte) bls.Signature {
+	return share.Key.Sign(message)
+}
+
+// VerifyPartial checks the partial signature of the message by the share
+// with the given index against the commitments
+func VerifyPartial(commitments Commitments, index uint32, message []byte, sig bls.Signature) bool {
+	return sig.Verify(commitments.SharePublicKey(index), message)
+}
+
+// LagrangeCoefficients calculates the coefficients interpolating the
+// polynomial at zero from its values at the given indices:
+//
+// Li = Πj xj / (xj - xi), j ≠ i
+func LagrangeCoefficients(indices []uint32) ([]big.Int, error) {
+	if len(indices) == 0 {
+		return nil, ErrNoShares
+	}
+	seen := make(map[uint32]struct{}, len(indices))
+	for _, index := range indices {
+		if index == 0 {
+			return nil, ErrInvalidIndex
+		}
+		if _, ok := seen[index]; ok {
+			return nil, ErrDuplicateIndex
+		}
+		seen[index] = struct{}{}
+	}
+
+	res := make([]big.Int, len(indices))
+	for i, xi := range indices {
+		num, den := big.NewInt(1), big.NewInt(1)
+		for j, xj := range indices {
+			if i == j {
+				continue
+			}
+			num.Mul(num, new(big.Int).SetUint64(uint64(xj)))
+			num.Mod(num, order)
+			den.Mul(den, new(big.Int).Sub(new(big.Int).SetUint64(uint64(xj)), new(big.Int).SetUint64(uint64(xi))))
+			den.Mod(den, order)
+		}
+		res[i].Mul(num, den.ModInverse(den, order))
+		res[i].Mod(&res[i], order)
+	}
+	return res, nil
+}
+
+// CombineSignatures interpolates the signature of the group public key from
+// the partial signatures of the shares with the given indices. At least
+// threshold valid partial signatures are required, otherwise the result is
+// not a valid signature.
+func CombineSignatures(sigs []bls.Signature, indices []uint32) (bls.Signature, error) {
+	if len(sigs) != len(indices) {
+		return bls.Signature{}, bls.ErrLengthMismatch
+	}
+	coefs, err := LagrangeCoefficients(indices)
+	if err != nil {
+		return bls.Signature{}, err
+	}
+	return bls.AggregateSignaturesChecked(sigs, coefs)
+}
+
+// Recover interpolates the private key from at least threshold shares
+func Recover(shares []Share) (bls.PrivateKey, error) {
+	indices := make([]uint32, len(shares))
+	for i, share := range shares {
+		indices[i] = share.Index
+	}
+	coefs, err := LagrangeCoefficients(indices)
+	if err != nil {
+		return bls.PrivateKey{}, err
+	}
+	res := new(big.Int)
+	for i, share := range shares {
+		scalar := share.Key.Scalar()
+		if scalar == nil {
+			return bls.PrivateKey{}, bls.ErrNilKey
+		}
+		res.Add(res, scalar.Mul(scalar, &coefs[i]))
+	}
+	return bls.PrivateKeyFromScalar(res.Mod(res, order))
+}
+
+// randomScalar returns a random non-zero scalar
+func randomScalar() (*big.Int, error) {
+	for {
+		k, err := rand.Int(rand.Reader, order)
+		if err != nil {
+			return nil, err
+		}
+		if k.Sign() != 0 {
+			return k, nil
+		}
+	}
+}